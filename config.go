@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultConfigFile = ".gql-gen-mcp.yaml"
+
+// Root represents the root of the YAML configuration file.
+type Root struct {
+	Schemas []Schema `yaml:"schemas"`
+}
+
+// Schema represents a schema configuration in the YAML file.
+type Schema struct {
+	Name string `yaml:"name"`
+	// Dir is a directory containing the .graphql or .graphqls schema files, subdirectories are included.
+	Dir string `yaml:"dir"`
+	// Files is a list of schema files or glob patterns, ** matches any number of directories.
+	// It can be combined with Dir.
+	Files []string `yaml:"files"`
+	// GqlgenConfig is the path to a gqlgen.yml file, the schema files configured in it are loaded.
+	// Federation is enabled when it is configured in the gqlgen.yml file. It can be combined with Dir and Files.
+	GqlgenConfig string `yaml:"gqlgen_config"`
+	// IntrospectionURL is the URL of a GraphQL API from which the schema is retrieved
+	// using an introspection query. It can be used instead of Dir.
+	IntrospectionURL string `yaml:"introspection_url"`
+	// Headers are sent along with the introspection query, environment variables are expanded.
+	Headers map[string]string `yaml:"headers"`
+	// IntrospectionFile is the path to a stored introspection result, e.g. schema.json.
+	// It can be used instead of Dir.
+	IntrospectionFile string `yaml:"introspection_file"`
+	// Federation configures Apollo Federation support, subgraph schemas are detected automatically.
+	Federation Federation `yaml:"federation"`
+	// Subgraphs is a list of subgraph schema directories or glob patterns which are composed into a
+	// single supergraph. It can be used instead of Dir.
+	Subgraphs []string `yaml:"subgraphs"`
+	// Include limits the generated tools to the operations matching one of the patterns.
+	// Patterns are exact names, globs such as get* or regular expressions enclosed in slashes.
+	// A pattern prefixed with query. or mutation. only matches operations of that type.
+	Include []string `yaml:"include"`
+	// Exclude skips the operations matching one of the patterns, it takes precedence over Include.
+	Exclude []string `yaml:"exclude"`
+	// QueriesOnly only generates tools for queries.
+	QueriesOnly bool `yaml:"queries_only"`
+	// NoMutations skips all mutations.
+	NoMutations bool `yaml:"no_mutations"`
+	// Scalars configures the JSON schema representation of custom scalars by scalar name.
+	// Common scalars such as UUID, DateTime and JSON are configured by default, other scalars are represented as string.
+	Scalars map[string]Scalar `yaml:"scalars"`
+	// Deprecated configures the handling of deprecated operations, arguments, input fields, response fields
+	// and enum values: include (default), mark or exclude.
+	Deprecated string `yaml:"deprecated"`
+	// MaxDepth limits the depth of the fields selected in the responses of the tools, 0 means unlimited.
+	MaxDepth int `yaml:"max_depth"`
+	// MaxFields limits the number of fields selected in the responses of the tools, 0 means unlimited.
+	// The fields are selected breadth-first.
+	MaxFields int `yaml:"max_fields"`
+	// CycleDepth is the number of times a type which references itself, directly or through other
+	// types, is expanded again in the responses of the tools. By default it is not selected again.
+	CycleDepth int `yaml:"cycle_depth"`
+	// Resources generates MCP resources exposing the schema as graphql://<name>/schema and resolving
+	// objects by their ID as graphql://<name>/<Type>/{id}.
+	Resources bool `yaml:"resources"`
+	// Subscriptions configures the tools generated for subscriptions.
+	Subscriptions Subscriptions `yaml:"subscriptions"`
+	// Operations is a directory containing .graphql operation documents. If set, a tool is generated for every
+	// named operation in the documents, instead of for every operation of the schema.
+	Operations string `yaml:"operations"`
+	// Tools configures individual tools by tool name.
+	Tools map[string]Tool `yaml:"tools"`
+	// SkipInvalid skips the operations for which no tool can be generated with a warning,
+	// instead of failing the generation.
+	SkipInvalid bool `yaml:"skip_invalid"`
+	// BaseURL is the default URL of the GraphQL API used by the generated server, e.g. the URL of the federated gateway.
+	BaseURL string `yaml:"base_url"`
+	Output  string `yaml:"output"`
+}
+
+// Scalar represents the JSON schema representation of a custom scalar.
+type Scalar struct {
+	// Type is the JSON schema type: string, number, integer, boolean, object or array. Defaults to string.
+	Type        string `yaml:"type"`
+	Format      string `yaml:"format"`
+	Pattern     string `yaml:"pattern"`
+	Description string `yaml:"description"`
+}
+
+// Tool represents the configuration of a single tool.
+type Tool struct {
+	// MaxDepth overrides the max_depth of the schema for the tool.
+	MaxDepth int `yaml:"max_depth"`
+	// MaxFields overrides the max_fields of the schema for the tool.
+	MaxFields int `yaml:"max_fields"`
+	// Selection overrides the fields selected in the response of the tool.
+	Selection Selection `yaml:"selection"`
+}
+
+// Selection overrides the fields selected in the response of a tool, either using an explicit
+// selection set or using include and exclude field paths.
+type Selection struct {
+	// Fields is an explicit GraphQL selection set without surrounding braces, e.g. "id title author { name }".
+	Fields string `yaml:"fields"`
+	// Include only selects the fields with the given dot separated paths, e.g. author.name.
+	Include []string `yaml:"include"`
+	// Exclude skips the fields with the given dot separated paths.
+	Exclude []string `yaml:"exclude"`
+}
+
+// Subscriptions represents the configuration of the tools generated for subscriptions. The tools
+// subscribe over a websocket and return the events collected within the bounds.
+type Subscriptions struct {
+	// Enabled generates a tool for every subscription.
+	Enabled bool `yaml:"enabled"`
+	// MaxEvents is the maximum number of events returned by a tool, 0 means unlimited.
+	MaxEvents int `yaml:"max_events"`
+	// Timeout is the maximum duration events are collected, e.g. 10s. Defaults to 30s.
+	Timeout time.Duration `yaml:"timeout"`
+	// Progress sends an MCP progress notification for every event.
+	Progress bool `yaml:"progress"`
+}
+
+// Federation represents the Apollo Federation configuration of a schema.
+type Federation struct {
+	// Enabled loads the schema as subgraph schema, even if it does not use any federation directives.
+	Enabled bool `yaml:"enabled"`
+	// EntityTools generates a lookup tool for every entity using the _entities query.
+	EntityTools bool `yaml:"entity_tools"`
+}
+
+// loadConfig reads the configuration file, relative paths in the configuration
+// are resolved against the directory of the configuration file.
+func loadConfig(path string) (*Root, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no %s file found: %w", path, err)
+	}
+	t := Root{}
+	err = yaml.Unmarshal(data, &t)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect yaml file: %w", err)
+	}
+	base := filepath.Dir(path)
+	for i := range t.Schemas {
+		t.Schemas[i].resolvePaths(base)
+	}
+	return &t, nil
+}
+
+func (s *Schema) resolvePaths(base string) {
+	s.Dir = resolvePath(base, s.Dir)
+	s.IntrospectionFile = resolvePath(base, s.IntrospectionFile)
+	s.GqlgenConfig = resolvePath(base, s.GqlgenConfig)
+	s.Operations = resolvePath(base, s.Operations)
+	for i := range s.Files {
+		s.Files[i] = resolvePath(base, s.Files[i])
+	}
+	s.Output = resolvePath(base, s.Output)
+	for i := range s.Subgraphs {
+		s.Subgraphs[i] = resolvePath(base, s.Subgraphs[i])
+	}
+}
+
+func resolvePath(base, path string) string {
+	if path == "" || base == "." || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(base, path)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// diagnosticsError reports every underlying error on its own line.
+type diagnosticsError struct {
+	msg         string
+	diagnostics []string
+	err         error
+}
+
+// newSchemaError wraps err with msg, GraphQL errors are reported as file:line:col: message.
+func newSchemaError(msg string, err error) error {
+	var list gqlerror.List
+	if !errors.As(err, &list) || len(list) == 0 {
+		var gqlErr *gqlerror.Error
+		if !errors.As(err, &gqlErr) {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		list = gqlerror.List{gqlErr}
+	}
+	diagnostics := make([]string, 0, len(list))
+	for _, e := range list {
+		diagnostics = append(diagnostics, diagnostic(e))
+	}
+	return &diagnosticsError{msg: msg, diagnostics: diagnostics, err: list}
+}
+
+// newToolsError wraps err with msg, errors joined using errors.Join are reported on their own line.
+func newToolsError(msg string, err error) error {
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint
+	if !ok {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	diagnostics := []string{}
+	for _, e := range joined.Unwrap() {
+		diagnostics = append(diagnostics, e.Error())
+	}
+	return &diagnosticsError{msg: msg, diagnostics: diagnostics, err: err}
+}
+
+func (e *diagnosticsError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.msg)
+	b.WriteString(":")
+	for _, d := range e.diagnostics {
+		b.WriteString("\n  ")
+		b.WriteString(d)
+	}
+	return b.String()
+}
+
+func (e *diagnosticsError) Unwrap() error {
+	return e.err
+}
+
+// diagnostic formats the error as file:line:col: message.
+func diagnostic(err *gqlerror.Error) string {
+	var b strings.Builder
+	file, _ := err.Extensions["file"].(string)
+	if file == "" {
+		file = "input"
+	}
+	b.WriteString(file)
+	if len(err.Locations) > 0 {
+		b.WriteString(":" + strconv.Itoa(err.Locations[0].Line))
+		if err.Locations[0].Column > 0 {
+			b.WriteString(":" + strconv.Itoa(err.Locations[0].Column))
+		}
+	}
+	b.WriteString(": ")
+	if len(err.Path) > 0 {
+		b.WriteString(err.Path.String() + " ")
+	}
+	b.WriteString(err.Message)
+	return b.String()
+}
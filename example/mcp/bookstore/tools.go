@@ -40,13 +40,8 @@ func (t *ToolRegistry) RegisterTools() {
 
 // RegisterBooksTool Retrieve a paginated list of books with optional filters and sorting.
 func (t *ToolRegistry) RegisterBooksTool() {
-	booksTool := mcp.NewTool("books",
-		mcp.WithDescription("Retrieve a paginated list of books with optional filters and sorting."),
-
-		mcp.WithObject("input",
-			mcp.Description(""),
-			mcp.Properties(map[string]any{"filter": map[string]any{"type": "object", "description": "Filters to apply when listing books.", "properties": map[string]any{"genre": map[string]any{"type": "string", "description": "Filter by the book's genre.", "enum": []string{"FICTION", "NON_FICTION", "SCIENCE", "HISTORY", "FANTASY", "BIOGRAPHY", "CHILDREN", "ROMANCE", "THRILLER", "MYSTERY", "SELF_HELP"}}, "status": map[string]any{"type": "string", "description": "Filter by the book's status (e.g., available, out of stock).", "enum": []string{"AVAILABLE", "OUT_OF_STOCK", "DISCONTINUED"}}, "authorId": map[string]any{"type": "string", "description": "Filter by the ID of the author of the book."}, "minPrice": map[string]any{"type": "number", "description": "Filter by the minimum price of the book."}, "maxPrice": map[string]any{"type": "number", "description": "Filter by the maximum price of the book."}, "publishedAfter": map[string]any{"type": "number", "description": "Filter by books published after a specific year."}, "publishedBefore": map[string]any{"type": "number", "description": "Filter by books published before a specific year."}, "searchText": map[string]any{"type": "string", "description": "Search text that matches the book's title or description."}}}, "first": map[string]any{"type": "number", "description": "The maximum number of books to return in the list. Defaults to 10."}, "after": map[string]any{"type": "string", "description": "The cursor to start retrieving books after."}, "sortBy": map[string]any{"type": "string", "description": "The field to sort the list of books by. Defaults to TITLE.", "enum": []string{"TITLE", "PUBLISHED_YEAR", "PRICE"}}}),
-		),
+	booksTool := mcp.NewToolWithRawSchema("books", "Retrieve a paginated list of books with optional filters and sorting.",
+		json.RawMessage(`{"type":"object","properties":{"input":{"anyOf":[{"$ref":"#/$defs/BookListInput"},{"type":"null"}]}},"$defs":{"BookFilterInput":{"type":"object","description":"Input for filtering books in a query.","properties":{"authorId":{"description":"Filter by the ID of the author of the book.","type":["string","null"]},"genre":{"description":"Filter by the book's genre.","type":["string","null"],"enum":["FICTION","NON_FICTION","SCIENCE","HISTORY","FANTASY","BIOGRAPHY","CHILDREN","ROMANCE","THRILLER","MYSTERY","SELF_HELP",null],"oneOf":[{"description":"A work of fiction or imaginative narrative.","const":"FICTION"},{"description":"A work based on real facts or events.","const":"NON_FICTION"},{"description":"A work related to scientific subjects.","const":"SCIENCE"},{"description":"A historical work or book about past events.","const":"HISTORY"},{"description":"A work of fantasy including magical or supernatural elements.","const":"FANTASY"},{"description":"A written account of someone's life experiences.","const":"BIOGRAPHY"},{"description":"A book intended for children or younger audiences.","const":"CHILDREN"},{"description":"A work primarily focused on romantic relationships.","const":"ROMANCE"},{"description":"A story with elements of suspense or excitement, usually including danger.","const":"THRILLER"},{"description":"A story that involves solving a crime or uncovering secrets.","const":"MYSTERY"},{"description":"A book intended to provide guidelines or advice on self-improvement.","const":"SELF_HELP"},{"type":"null"}]},"maxPrice":{"description":"Filter by the maximum price of the book.","type":["number","null"]},"minPrice":{"description":"Filter by the minimum price of the book.","type":["number","null"]},"publishedAfter":{"description":"Filter by books published after a specific year.","type":["integer","null"]},"publishedBefore":{"description":"Filter by books published before a specific year.","type":["integer","null"]},"searchText":{"description":"Search text that matches the book's title or description.","type":["string","null"]},"status":{"description":"Filter by the book's status (e.g., available, out of stock).","type":["string","null"],"enum":["AVAILABLE","OUT_OF_STOCK","DISCONTINUED",null],"oneOf":[{"description":"The book is available for purchase.","const":"AVAILABLE"},{"description":"The book is currently out of stock.","const":"OUT_OF_STOCK"},{"description":"The book is no longer being sold.","const":"DISCONTINUED"},{"type":"null"}]}}},"BookListInput":{"type":"object","description":"Input for listing books with pagination, sorting, and filtering.","properties":{"after":{"description":"The cursor to start retrieving books after.","type":["string","null"]},"filter":{"description":"Filters to apply when listing books.","anyOf":[{"$ref":"#/$defs/BookFilterInput"},{"type":"null"}]},"first":{"description":"The maximum number of books to return in the list. Defaults to 10.","default":10,"type":["integer","null"]},"sortBy":{"description":"The field to sort the list of books by. Defaults to TITLE.","default":"TITLE","type":["string","null"],"enum":["TITLE","PUBLISHED_YEAR","PRICE",null],"oneOf":[{"description":"Sort by the book's title.","const":"TITLE"},{"description":"Sort by the year the book was published.","const":"PUBLISHED_YEAR"},{"description":"Sort by the price of the book.","const":"PRICE"},{"type":"null"}]}}}}}`),
 	)
 	t.MCPServer.AddTool(booksTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -99,13 +94,8 @@ func (t *ToolRegistry) RegisterBooksTool() {
 
 // RegisterBookTool Retrieve a single book by its unique ID.
 func (t *ToolRegistry) RegisterBookTool() {
-	bookTool := mcp.NewTool("book",
-		mcp.WithDescription("Retrieve a single book by its unique ID."),
-
-		mcp.WithString("id",
-			mcp.Description(""),
-			mcp.Required(),
-		),
+	bookTool := mcp.NewToolWithRawSchema("book", "Retrieve a single book by its unique ID.",
+		json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
 	)
 	t.MCPServer.AddTool(bookTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -146,13 +136,8 @@ func (t *ToolRegistry) RegisterBookTool() {
 
 // RegisterAuthorTool Retrieve a single author by their unique ID.
 func (t *ToolRegistry) RegisterAuthorTool() {
-	authorTool := mcp.NewTool("author",
-		mcp.WithDescription("Retrieve a single author by their unique ID."),
-
-		mcp.WithString("id",
-			mcp.Description(""),
-			mcp.Required(),
-		),
+	authorTool := mcp.NewToolWithRawSchema("author", "Retrieve a single author by their unique ID.",
+		json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
 	)
 	t.MCPServer.AddTool(authorTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -193,8 +178,8 @@ func (t *ToolRegistry) RegisterAuthorTool() {
 
 // RegisterAuthorsTool Retrieve a list of all authors.
 func (t *ToolRegistry) RegisterAuthorsTool() {
-	authorsTool := mcp.NewTool("authors",
-		mcp.WithDescription("Retrieve a list of all authors."),
+	authorsTool := mcp.NewToolWithRawSchema("authors", "Retrieve a list of all authors.",
+		json.RawMessage(`{"type":"object"}`),
 	)
 	t.MCPServer.AddTool(authorsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -235,14 +220,8 @@ func (t *ToolRegistry) RegisterAuthorsTool() {
 
 // RegisterCreateBookTool Create a new book entry in the store.
 func (t *ToolRegistry) RegisterCreateBookTool() {
-	createBookTool := mcp.NewTool("createBook",
-		mcp.WithDescription("Create a new book entry in the store."),
-
-		mcp.WithObject("input",
-			mcp.Description(""),
-			mcp.Required(),
-			mcp.Properties(map[string]any{"title": map[string]any{"type": "string", "description": "The title of the book."}, "description": map[string]any{"type": "string", "description": "A brief description of the book's content."}, "publishedYear": map[string]any{"type": "number", "description": "The year the book was published."}, "genre": map[string]any{"type": "string", "description": "The genre of the book.", "enum": []string{"FICTION", "NON_FICTION", "SCIENCE", "HISTORY", "FANTASY", "BIOGRAPHY", "CHILDREN", "ROMANCE", "THRILLER", "MYSTERY", "SELF_HELP"}}, "price": map[string]any{"type": "number", "description": "The price of the book."}, "status": map[string]any{"type": "string", "description": "The status of the book (e.g., available, out of stock).", "enum": []string{"AVAILABLE", "OUT_OF_STOCK", "DISCONTINUED"}}, "authorId": map[string]any{"type": "string", "description": "The ID of the author who wrote the book."}}),
-		),
+	createBookTool := mcp.NewToolWithRawSchema("createBook", "Create a new book entry in the store.",
+		json.RawMessage(`{"type":"object","properties":{"input":{"$ref":"#/$defs/CreateBookInput"}},"required":["input"],"$defs":{"CreateBookInput":{"type":"object","description":"Input for creating a new book.","properties":{"authorId":{"type":"string","description":"The ID of the author who wrote the book."},"description":{"description":"A brief description of the book's content.","type":["string","null"]},"genre":{"type":"string","description":"The genre of the book.","enum":["FICTION","NON_FICTION","SCIENCE","HISTORY","FANTASY","BIOGRAPHY","CHILDREN","ROMANCE","THRILLER","MYSTERY","SELF_HELP"],"oneOf":[{"description":"A work of fiction or imaginative narrative.","const":"FICTION"},{"description":"A work based on real facts or events.","const":"NON_FICTION"},{"description":"A work related to scientific subjects.","const":"SCIENCE"},{"description":"A historical work or book about past events.","const":"HISTORY"},{"description":"A work of fantasy including magical or supernatural elements.","const":"FANTASY"},{"description":"A written account of someone's life experiences.","const":"BIOGRAPHY"},{"description":"A book intended for children or younger audiences.","const":"CHILDREN"},{"description":"A work primarily focused on romantic relationships.","const":"ROMANCE"},{"description":"A story with elements of suspense or excitement, usually including danger.","const":"THRILLER"},{"description":"A story that involves solving a crime or uncovering secrets.","const":"MYSTERY"},{"description":"A book intended to provide guidelines or advice on self-improvement.","const":"SELF_HELP"}]},"price":{"type":"number","description":"The price of the book."},"publishedYear":{"description":"The year the book was published.","type":["integer","null"]},"status":{"type":"string","description":"The status of the book (e.g., available, out of stock).","enum":["AVAILABLE","OUT_OF_STOCK","DISCONTINUED"],"oneOf":[{"description":"The book is available for purchase.","const":"AVAILABLE"},{"description":"The book is currently out of stock.","const":"OUT_OF_STOCK"},{"description":"The book is no longer being sold.","const":"DISCONTINUED"}]},"title":{"type":"string","description":"The title of the book."}},"required":["title","genre","price","status","authorId"]}}}`),
 	)
 	t.MCPServer.AddTool(createBookTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -283,18 +262,8 @@ func (t *ToolRegistry) RegisterCreateBookTool() {
 
 // RegisterUpdateBookTool Update an existing book.
 func (t *ToolRegistry) RegisterUpdateBookTool() {
-	updateBookTool := mcp.NewTool("updateBook",
-		mcp.WithDescription("Update an existing book."),
-
-		mcp.WithString("id",
-			mcp.Description(""),
-			mcp.Required(),
-		),
-		mcp.WithObject("input",
-			mcp.Description(""),
-			mcp.Required(),
-			mcp.Properties(map[string]any{"title": map[string]any{"type": "string", "description": "Update the title of the book."}, "description": map[string]any{"type": "string", "description": "Update the description of the book's content."}, "publishedYear": map[string]any{"type": "number", "description": "Update the year the book was published."}, "genre": map[string]any{"type": "string", "description": "Update the genre of the book.", "enum": []string{"FICTION", "NON_FICTION", "SCIENCE", "HISTORY", "FANTASY", "BIOGRAPHY", "CHILDREN", "ROMANCE", "THRILLER", "MYSTERY", "SELF_HELP"}}, "price": map[string]any{"type": "number", "description": "Update the price of the book."}, "status": map[string]any{"type": "string", "description": "Update the status of the book (e.g., available, out of stock).", "enum": []string{"AVAILABLE", "OUT_OF_STOCK", "DISCONTINUED"}}, "authorId": map[string]any{"type": "string", "description": "Update the ID of the author who wrote the book."}}),
-		),
+	updateBookTool := mcp.NewToolWithRawSchema("updateBook", "Update an existing book.",
+		json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"},"input":{"$ref":"#/$defs/UpdateBookInput"}},"required":["id","input"],"$defs":{"UpdateBookInput":{"type":"object","description":"Input for updating an existing book.","properties":{"authorId":{"description":"Update the ID of the author who wrote the book.","type":["string","null"]},"description":{"description":"Update the description of the book's content.","type":["string","null"]},"genre":{"description":"Update the genre of the book.","type":["string","null"],"enum":["FICTION","NON_FICTION","SCIENCE","HISTORY","FANTASY","BIOGRAPHY","CHILDREN","ROMANCE","THRILLER","MYSTERY","SELF_HELP",null],"oneOf":[{"description":"A work of fiction or imaginative narrative.","const":"FICTION"},{"description":"A work based on real facts or events.","const":"NON_FICTION"},{"description":"A work related to scientific subjects.","const":"SCIENCE"},{"description":"A historical work or book about past events.","const":"HISTORY"},{"description":"A work of fantasy including magical or supernatural elements.","const":"FANTASY"},{"description":"A written account of someone's life experiences.","const":"BIOGRAPHY"},{"description":"A book intended for children or younger audiences.","const":"CHILDREN"},{"description":"A work primarily focused on romantic relationships.","const":"ROMANCE"},{"description":"A story with elements of suspense or excitement, usually including danger.","const":"THRILLER"},{"description":"A story that involves solving a crime or uncovering secrets.","const":"MYSTERY"},{"description":"A book intended to provide guidelines or advice on self-improvement.","const":"SELF_HELP"},{"type":"null"}]},"price":{"description":"Update the price of the book.","type":["number","null"]},"publishedYear":{"description":"Update the year the book was published.","type":["integer","null"]},"status":{"description":"Update the status of the book (e.g., available, out of stock).","type":["string","null"],"enum":["AVAILABLE","OUT_OF_STOCK","DISCONTINUED",null],"oneOf":[{"description":"The book is available for purchase.","const":"AVAILABLE"},{"description":"The book is currently out of stock.","const":"OUT_OF_STOCK"},{"description":"The book is no longer being sold.","const":"DISCONTINUED"},{"type":"null"}]},"title":{"description":"Update the title of the book.","type":["string","null"]}}}}}`),
 	)
 	t.MCPServer.AddTool(updateBookTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -335,13 +304,8 @@ func (t *ToolRegistry) RegisterUpdateBookTool() {
 
 // RegisterDeleteBookTool Delete a book by its unique ID.
 func (t *ToolRegistry) RegisterDeleteBookTool() {
-	deleteBookTool := mcp.NewTool("deleteBook",
-		mcp.WithDescription("Delete a book by its unique ID."),
-
-		mcp.WithString("id",
-			mcp.Description(""),
-			mcp.Required(),
-		),
+	deleteBookTool := mcp.NewToolWithRawSchema("deleteBook", "Delete a book by its unique ID.",
+		json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
 	)
 	t.MCPServer.AddTool(deleteBookTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		var res map[string]any
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// gqlgenConfig represents the parts of a gqlgen.yml configuration file which are used by gql-gen-mcp.
+type gqlgenConfig struct {
+	// Schema contains the schema files or glob patterns, relative to the gqlgen.yml file.
+	Schema     stringList       `yaml:"schema"`
+	Federation gqlgenFederation `yaml:"federation"`
+}
+
+type gqlgenFederation struct {
+	Filename string `yaml:"filename"`
+	Version  int    `yaml:"version"`
+}
+
+// stringList is a YAML value which is either a single string or a list of strings.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = stringList{value.Value}
+		return nil
+	}
+	var list []string
+	err := value.Decode(&list)
+	if err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// loadGqlgenConfig reads the gqlgen.yml file, the schema patterns are resolved
+// against the directory of the gqlgen.yml file.
+func loadGqlgenConfig(path string) (*gqlgenConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading gqlgen config: %w", err)
+	}
+	config := gqlgenConfig{}
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect gqlgen config %s: %w", path, err)
+	}
+	if len(config.Schema) == 0 {
+		// gqlgen defaults to schema.graphql when no schema is configured.
+		config.Schema = stringList{"schema.graphql"}
+	}
+	base := filepath.Dir(path)
+	for i := range config.Schema {
+		config.Schema[i] = resolvePath(base, config.Schema[i])
+	}
+	return &config, nil
+}
+
+// federationEnabled reports whether gqlgen generates a federated subgraph.
+func (c *gqlgenConfig) federationEnabled() bool {
+	return c.Federation.Filename != "" || c.Federation.Version != 0
+}
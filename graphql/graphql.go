@@ -29,9 +29,10 @@ type response struct {
 
 // Client is a GraphQL client that can be used to send requests to a GraphQL server.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
-	hooks      []HTTPRequestHook
+	baseURL         string
+	subscriptionURL string
+	httpClient      *http.Client
+	hooks           []HTTPRequestHook
 }
 
 // NewDefaultClient creates a new GraphQL client with the default HTTP client and the provided base URL.
@@ -85,10 +86,10 @@ func (c *Client) Call(ctx context.Context, request Request, result any) error {
 		return fmt.Errorf("unable to read body: %w", err)
 	}
 
-	return c.processResponse(body, result)
+	return processResponse(body, result)
 }
 
-func (c *Client) processResponse(body []byte, result any) error {
+func processResponse(body []byte, result any) error {
 	gqlResponse := response{}
 	err := json.Unmarshal(body, &gqlResponse)
 	if err != nil {
@@ -0,0 +1,210 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Websocket subprotocols supported for subscriptions.
+const (
+	// ProtocolTransportWS is the graphql-transport-ws protocol of the graphql-ws library.
+	ProtocolTransportWS = "graphql-transport-ws"
+	// ProtocolGraphQLWS is the legacy graphql-ws protocol of the subscriptions-transport-ws library.
+	ProtocolGraphQLWS = "graphql-ws"
+)
+
+// subscriptionID is the ID of the only subscription of a websocket connection.
+const subscriptionID = "1"
+
+// ErrStopSubscription can be returned by an EventHandler to stop the subscription without error.
+var ErrStopSubscription = errors.New("stop subscription")
+
+// EventHandler is called with the data of every event of a subscription.
+type EventHandler func(data json.RawMessage) error
+
+// CollectOptions bounds the events collected by Collect.
+type CollectOptions struct {
+	// MaxEvents is the maximum number of events, 0 means unlimited.
+	MaxEvents int
+	// Timeout is the maximum duration events are collected, 0 means until the subscription completes.
+	Timeout time.Duration
+	// OnEvent is called with the number of events collected so far, e.g. to report progress.
+	OnEvent func(count int)
+}
+
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// WithSubscriptionURL sets the websocket URL used for subscriptions, which defaults to the base URL
+// using the ws or wss scheme.
+func (c *Client) WithSubscriptionURL(url string) *Client {
+	c.subscriptionURL = url
+	return c
+}
+
+func (c *Client) websocketURL() string {
+	if c.subscriptionURL != "" {
+		return c.subscriptionURL
+	}
+	if rest, ok := strings.CutPrefix(c.baseURL, "https://"); ok {
+		return "wss://" + rest
+	}
+	if rest, ok := strings.CutPrefix(c.baseURL, "http://"); ok {
+		return "ws://" + rest
+	}
+	return c.baseURL
+}
+
+// Collect executes a GraphQL subscription and returns the data of the events received until the
+// subscription completes or the bounds of the options are reached.
+func (c *Client) Collect(ctx context.Context, request Request, options CollectOptions) ([]json.RawMessage, error) {
+	subscriptionCtx := ctx
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		subscriptionCtx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	events := []json.RawMessage{}
+	err := c.Subscribe(subscriptionCtx, request, func(data json.RawMessage) error {
+		events = append(events, data)
+		if options.OnEvent != nil {
+			options.OnEvent(len(events))
+		}
+		if options.MaxEvents > 0 && len(events) >= options.MaxEvents {
+			return ErrStopSubscription
+		}
+		return nil
+	})
+	// reaching the timeout ends the collection, unless the parent context is done.
+	if err != nil && (ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded)) {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Subscribe executes a GraphQL subscription over a websocket using the graphql-transport-ws protocol, or the
+// legacy graphql-ws protocol if the server only supports that one. The request hooks are applied to the
+// websocket handshake. handle is called for every event until the server completes the subscription,
+// handle returns an error or the context is done.
+func (c *Client) Subscribe(ctx context.Context, request Request, handle EventHandler) error { //nolint:revive
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.websocketURL(), nil)
+	if err != nil {
+		return fmt.Errorf("create request struct failed: %w", err)
+	}
+	for _, hook := range c.hooks {
+		if err := hook(req); err != nil {
+			return fmt.Errorf("request hook failed: %w", err)
+		}
+	}
+	dialer := *websocket.DefaultDialer
+	dialer.Subprotocols = []string{ProtocolTransportWS, ProtocolGraphQLWS}
+	conn, resp, err := dialer.DialContext(ctx, req.URL.String(), req.Header)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("websocket dial failed: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	// closing the connection when the context is done unblocks pending reads.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	s := &subscription{conn: conn, legacy: conn.Subprotocol() == ProtocolGraphQLWS}
+	err = s.run(request, handle)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// subscription runs a single subscription on a websocket connection.
+type subscription struct {
+	conn *websocket.Conn
+	// legacy is set when the connection uses the graphql-ws protocol.
+	legacy bool
+}
+
+func (s *subscription) run(request Request, handle EventHandler) error { //nolint:revive
+	if err := s.write(wsMessage{Type: "connection_init", Payload: json.RawMessage("{}")}); err != nil {
+		return err
+	}
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshal request struct failed: %w", err)
+	}
+	subscribed := false
+	for {
+		var msg wsMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			return fmt.Errorf("read message failed: %w", err)
+		}
+		switch msg.Type {
+		case "connection_ack":
+			if subscribed {
+				continue
+			}
+			subscribed = true
+			start := "subscribe"
+			if s.legacy {
+				start = "start"
+			}
+			if err := s.write(wsMessage{ID: subscriptionID, Type: start, Payload: payload}); err != nil {
+				return err
+			}
+		case "next", "data":
+			var data json.RawMessage
+			if err := processResponse(msg.Payload, &data); err != nil {
+				return err
+			}
+			err := handle(data)
+			if errors.Is(err, ErrStopSubscription) {
+				return s.stop()
+			}
+			if err != nil {
+				return err
+			}
+		case "error", "connection_error":
+			return fmt.Errorf("graphql error: %s", string(msg.Payload))
+		case "complete":
+			return nil
+		case "ping":
+			if err := s.write(wsMessage{Type: "pong"}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// stop completes the subscription and closes the connection.
+func (s *subscription) stop() error {
+	stop := "complete"
+	if s.legacy {
+		stop = "stop"
+	}
+	if err := s.write(wsMessage{ID: subscriptionID, Type: stop}); err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func (s *subscription) write(msg wsMessage) error {
+	if err := s.conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("write message failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// subscriptionServer sends events counting up from 1 for every subscription, until count events
+// are sent or the client stops the subscription. A count of 0 sends events until the client stops.
+func subscriptionServer(t *testing.T, protocols []string, count int) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{Subprotocols: protocols}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		legacy := conn.Subprotocol() == ProtocolGraphQLWS
+		for {
+			var msg wsMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			switch msg.Type {
+			case "connection_init":
+				_ = conn.WriteJSON(wsMessage{Type: "connection_ack"})
+			case "subscribe", "start":
+				var request Request
+				_ = json.Unmarshal(msg.Payload, &request)
+				if request.OperationName == "invalid" {
+					_ = conn.WriteJSON(wsMessage{ID: msg.ID, Type: "error", Payload: json.RawMessage(`[{"message":"invalid"}]`)})
+					continue
+				}
+				next := "next"
+				if legacy {
+					next = "data"
+				}
+				go func() {
+					for i := 1; count == 0 || i <= count; i++ {
+						payload := fmt.Sprintf(`{"data":{"counter":%d}}`, i)
+						if err := conn.WriteJSON(wsMessage{ID: msg.ID, Type: next, Payload: json.RawMessage(payload)}); err != nil {
+							return
+						}
+						time.Sleep(10 * time.Millisecond)
+					}
+					_ = conn.WriteJSON(wsMessage{ID: msg.ID, Type: "complete"})
+				}()
+			case "complete", "stop":
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	auth := func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer token")
+		return nil
+	}
+	tests := []struct {
+		name      string
+		protocols []string
+		count     int
+		options   CollectOptions
+		expected  int
+	}{
+		{
+			name:      "completed",
+			protocols: []string{ProtocolTransportWS},
+			count:     3,
+			expected:  3,
+		},
+		{
+			name:      "max events",
+			protocols: []string{ProtocolTransportWS},
+			options:   CollectOptions{MaxEvents: 2},
+			expected:  2,
+		},
+		{
+			name:      "legacy protocol",
+			protocols: []string{ProtocolGraphQLWS},
+			options:   CollectOptions{MaxEvents: 2},
+			expected:  2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			server := subscriptionServer(t, test.protocols, test.count)
+			client := NewDefaultClient(server.URL, auth)
+			progress := 0
+			test.options.OnEvent = func(count int) {
+				progress = count
+			}
+			events, err := client.Collect(context.Background(), Request{
+				Query:         "subscription counter { counter }",
+				OperationName: "counter",
+			}, test.options)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, len(events))
+			assert.Equal(t, test.expected, progress)
+			assert.JSONEq(t, `{"counter":1}`, string(events[0]))
+		})
+	}
+
+	server := subscriptionServer(t, []string{ProtocolTransportWS}, 0)
+	events, err := NewDefaultClient(server.URL, auth).Collect(context.Background(), Request{}, CollectOptions{Timeout: 100 * time.Millisecond})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, events)
+
+	_, err = NewDefaultClient(server.URL, auth).Collect(context.Background(), Request{OperationName: "invalid"}, CollectOptions{})
+	assert.EqualError(t, err, `graphql error: [{"message":"invalid"}]`)
+
+	_, err = NewDefaultClient(server.URL).Collect(context.Background(), Request{}, CollectOptions{})
+	assert.ErrorContains(t, err, "websocket dial failed")
+}
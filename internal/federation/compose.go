@@ -0,0 +1,276 @@
+package federation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Subgraph represents a named subgraph schema which is part of a supergraph.
+type Subgraph struct {
+	Name    string
+	Sources []*ast.Source
+}
+
+const inaccessibleDirective = "inaccessible"
+
+// Compose composes the subgraphs into a single supergraph schema as exposed by a federated gateway.
+// Object and interface types are merged by combining the fields of all subgraphs, fields marked as
+// @external are only taken into account if no other subgraph resolves them. Input objects contain the
+// fields which are defined in every subgraph. Federation specific types, fields and directives are removed.
+func Compose(subgraphs ...Subgraph) (*ast.Schema, error) {
+	c := &composer{
+		types:      map[string]*ast.Definition{},
+		directives: map[string]*ast.DirectiveDefinition{},
+		origin:     map[string]string{},
+		inputs:     map[string]map[string]int{},
+		inputCount: map[string]int{},
+	}
+	for _, subgraph := range subgraphs {
+		schema, err := LoadSchema(subgraph.Sources...)
+		if err != nil {
+			return nil, fmt.Errorf("error loading subgraph %s: %w", subgraph.Name, err)
+		}
+		err = c.add(subgraph.Name, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.finalise()
+	return c.schema()
+}
+
+type composer struct {
+	doc        ast.SchemaDocument
+	types      map[string]*ast.Definition
+	directives map[string]*ast.DirectiveDefinition
+	// origin tracks the subgraph which first defined a type.
+	origin map[string]string
+	// external holds the @external fields per type, which are added when no subgraph resolves them.
+	external []externalField
+	// inputs tracks per input object in how many subgraphs each of its fields is defined.
+	inputs map[string]map[string]int
+	// inputCount tracks the number of subgraphs defining an input object.
+	inputCount map[string]int
+}
+
+type externalField struct {
+	typeName string
+	field    *ast.FieldDefinition
+}
+
+func (c *composer) add(subgraph string, schema *ast.Schema) error {
+	roots := map[string]string{}
+	if schema.Query != nil {
+		roots[schema.Query.Name] = "Query"
+	}
+	if schema.Mutation != nil {
+		roots[schema.Mutation.Name] = "Mutation"
+	}
+	if schema.Subscription != nil {
+		roots[schema.Subscription.Name] = "Subscription"
+	}
+
+	for _, name := range sortedKeys(schema.Directives) {
+		def := schema.Directives[name]
+		if isInjected(def.Position) || c.directives[name] != nil {
+			continue
+		}
+		c.directives[name] = def
+		c.doc.Directives = append(c.doc.Directives, def)
+	}
+
+	for _, name := range sortedKeys(schema.Types) {
+		def := schema.Types[name]
+		if isInjected(def.Position) || hasDirective(def.Directives, inaccessibleDirective) {
+			continue
+		}
+		if root, ok := roots[name]; ok {
+			name = root
+		}
+		err := c.addDefinition(subgraph, name, def, schema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *composer) addDefinition(subgraph, name string, def *ast.Definition, schema *ast.Schema) error {
+	existing, ok := c.types[name]
+	if !ok {
+		existing = &ast.Definition{
+			Kind:        def.Kind,
+			Description: def.Description,
+			Name:        name,
+			Directives:  c.filterDirectives(def.Directives, schema),
+			Position:    def.Position,
+		}
+		c.types[name] = existing
+		c.origin[name] = subgraph
+		c.doc.Definitions = append(c.doc.Definitions, existing)
+	}
+	if existing.Kind != def.Kind {
+		return fmt.Errorf("type %s is defined as %s in subgraph %s and as %s in subgraph %s",
+			name, existing.Kind, c.origin[name], def.Kind, subgraph)
+	}
+	if existing.Description == "" {
+		existing.Description = def.Description
+	}
+	existing.Interfaces = appendUnique(existing.Interfaces, def.Interfaces...)
+	existing.Types = appendUnique(existing.Types, def.Types...)
+
+	for _, v := range def.EnumValues {
+		if existing.EnumValues.ForName(v.Name) != nil || hasDirective(v.Directives, inaccessibleDirective) {
+			continue
+		}
+		value := *v
+		value.Directives = c.filterDirectives(v.Directives, schema)
+		existing.EnumValues = append(existing.EnumValues, &value)
+	}
+
+	if def.Kind == ast.InputObject {
+		c.addInputFields(existing, def, schema)
+		return nil
+	}
+	for _, f := range def.Fields {
+		if isFederationField(f) || hasDirective(f.Directives, inaccessibleDirective) {
+			continue
+		}
+		if hasDirective(f.Directives, "external") {
+			c.external = append(c.external, externalField{typeName: name, field: c.copyField(f, schema)})
+			continue
+		}
+		if existing.Fields.ForName(f.Name) != nil {
+			continue
+		}
+		existing.Fields = append(existing.Fields, c.copyField(f, schema))
+	}
+	return nil
+}
+
+func (c *composer) addInputFields(existing, def *ast.Definition, schema *ast.Schema) {
+	if c.inputs[existing.Name] == nil {
+		c.inputs[existing.Name] = map[string]int{}
+	}
+	c.inputCount[existing.Name]++
+	for _, f := range def.Fields {
+		if hasDirective(f.Directives, inaccessibleDirective) {
+			continue
+		}
+		c.inputs[existing.Name][f.Name]++
+		if existing.Fields.ForName(f.Name) != nil {
+			continue
+		}
+		existing.Fields = append(existing.Fields, c.copyField(f, schema))
+	}
+}
+
+// finalise adds the @external fields which are not resolved by any subgraph,
+// and removes the input object fields which are not defined in all subgraphs.
+func (c *composer) finalise() {
+	for _, e := range c.external {
+		def := c.types[e.typeName]
+		if def.Fields.ForName(e.field.Name) != nil {
+			continue
+		}
+		def.Fields = append(def.Fields, e.field)
+	}
+	for name, fieldCount := range c.inputs {
+		def := c.types[name]
+		fields := ast.FieldList{}
+		for _, f := range def.Fields {
+			if fieldCount[f.Name] == c.inputCount[name] {
+				fields = append(fields, f)
+			}
+		}
+		def.Fields = fields
+	}
+}
+
+func (c *composer) copyField(f *ast.FieldDefinition, schema *ast.Schema) *ast.FieldDefinition {
+	field := *f
+	field.Directives = c.filterDirectives(f.Directives, schema)
+	field.Arguments = ast.ArgumentDefinitionList{}
+	for _, a := range f.Arguments {
+		if hasDirective(a.Directives, inaccessibleDirective) {
+			continue
+		}
+		arg := *a
+		arg.Directives = c.filterDirectives(a.Directives, schema)
+		field.Arguments = append(field.Arguments, &arg)
+	}
+	return &field
+}
+
+// filterDirectives removes the usages of federation directives, only built-in
+// directives and directives defined by the subgraphs themselves are kept.
+func (c *composer) filterDirectives(directives ast.DirectiveList, schema *ast.Schema) ast.DirectiveList {
+	res := ast.DirectiveList{}
+	for _, d := range directives {
+		def := schema.Directives[d.Name]
+		if def == nil || (isInjected(def.Position) && !isBuiltInDirective(d.Name)) {
+			continue
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+func (c *composer) schema() (*ast.Schema, error) {
+	prelude, err := parser.ParseSchema(validator.Prelude)
+	if err != nil {
+		return nil, err
+	}
+	prelude.Merge(&c.doc)
+	return validator.ValidateSchemaDocument(prelude)
+}
+
+func isBuiltInDirective(name string) bool {
+	switch name {
+	case "deprecated", "specifiedBy", "oneOf":
+		return true
+	default:
+		return false
+	}
+}
+
+func isInjected(pos *ast.Position) bool {
+	return pos != nil && pos.Src != nil && pos.Src.BuiltIn
+}
+
+func isFederationField(f *ast.FieldDefinition) bool {
+	return f.Name == EntitiesField || f.Name == ServiceField || f.Name == "__schema" || f.Name == "__type"
+}
+
+func hasDirective(directives ast.DirectiveList, name string) bool {
+	return directives.ForName(name) != nil
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, l := range list {
+			if l == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
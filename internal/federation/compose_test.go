@@ -0,0 +1,128 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	accounts := Subgraph{
+		Name: "accounts",
+		Sources: []*ast.Source{{
+			Name: "accounts.graphql",
+			Input: `
+extend schema @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable", "@inaccessible"])
+
+"A user of the platform."
+type User @key(fields: "id") {
+  id: ID!
+  username: String!
+  passwordHash: String! @inaccessible
+}
+
+input UserFilter {
+  username: String
+  onlyActive: Boolean
+}
+
+type Query {
+  me: User
+  users(filter: UserFilter): [User!]!
+}
+`,
+		}},
+	}
+	reviews := Subgraph{
+		Name: "reviews",
+		Sources: []*ast.Source{{
+			Name: "reviews.graphql",
+			Input: `
+type Review @key(fields: "id") {
+  id: ID!
+  body: String!
+  author: User!
+}
+
+extend type User @key(fields: "id") {
+  id: ID! @external
+  reviews: [Review!]!
+}
+
+input UserFilter {
+  username: String
+}
+
+enum Rating {
+  GOOD
+  BAD @deprecated(reason: "be nice")
+}
+
+type RootMutation {
+  addReview(body: String!, rating: Rating): Review!
+}
+
+schema {
+  query: RootQuery
+  mutation: RootMutation
+}
+
+type RootQuery {
+  reviews: [Review!]!
+}
+`,
+		}},
+	}
+
+	schema, err := Compose(accounts, reviews)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Query", schema.Query.Name)
+	assert.Equal(t, "Mutation", schema.Mutation.Name)
+	assert.NotNil(t, schema.Query.Fields.ForName("me"))
+	assert.NotNil(t, schema.Query.Fields.ForName("reviews"))
+	assert.Nil(t, schema.Query.Fields.ForName(EntitiesField))
+	assert.Nil(t, schema.Query.Fields.ForName(ServiceField))
+	assert.NotNil(t, schema.Mutation.Fields.ForName("addReview"))
+
+	user := schema.Types["User"]
+	require.NotNil(t, user)
+	assert.Equal(t, "A user of the platform.", user.Description)
+	assert.Empty(t, user.Directives)
+	fieldNames := []string{}
+	for _, f := range user.Fields {
+		fieldNames = append(fieldNames, f.Name)
+	}
+	assert.Equal(t, []string{"id", "username", "reviews"}, fieldNames)
+
+	filter := schema.Types["UserFilter"]
+	require.NotNil(t, filter)
+	assert.Len(t, filter.Fields, 1)
+	assert.Equal(t, "username", filter.Fields[0].Name)
+
+	assert.NotNil(t, schema.Types["Rating"].EnumValues.ForName("BAD").Directives.ForName("deprecated"))
+	assert.Nil(t, schema.Types[EntityUnion])
+	assert.Nil(t, schema.Types["_Service"])
+	assert.Nil(t, schema.Directives["key"])
+}
+
+func TestComposeConflictingKinds(t *testing.T) {
+	t.Parallel()
+
+	_, err := Compose(
+		Subgraph{Name: "a", Sources: []*ast.Source{{Input: `type Query { status: Status } enum Status { OK }`}}},
+		Subgraph{Name: "b", Sources: []*ast.Source{{Input: `type Query { other: Status } type Status { ok: Boolean }`}}},
+	)
+	assert.ErrorContains(t, err, "type Status is defined as ENUM in subgraph a and as OBJECT in subgraph b")
+}
+
+func TestComposeInvalidSubgraph(t *testing.T) {
+	t.Parallel()
+
+	_, err := Compose(Subgraph{Name: "broken", Sources: []*ast.Source{{Input: `type Query { field: Unknown }`}}})
+	assert.ErrorContains(t, err, "error loading subgraph broken")
+}
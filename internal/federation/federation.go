@@ -0,0 +1,261 @@
+// Package federation loads Apollo Federation subgraph schemas into a GraphQL AST schema.
+package federation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Version represents the Apollo Federation version of a subgraph schema.
+type Version int
+
+// Federation versions.
+const (
+	// Version1 is Apollo Federation 1.
+	Version1 Version = 1
+	// Version2 is Apollo Federation 2, which is opted into using the @link directive.
+	Version2 Version = 2
+)
+
+const (
+	// EntityUnion is the name of the union of all entity types.
+	EntityUnion = "_Entity"
+	// EntitiesField is the name of the root query field used to resolve entities by their keys.
+	EntitiesField = "_entities"
+	// ServiceField is the name of the root query field exposing the subgraph SDL.
+	ServiceField = "_service"
+	// KeyDirective is the name of the directive marking a type as entity.
+	KeyDirective = "key"
+
+	federationV2URL = "specs.apollo.dev/federation/v2"
+)
+
+type definition struct {
+	name string
+	sdl  string
+}
+
+func federationV1Prelude() []definition {
+	return []definition{
+		{"key", `directive @key(fields: _FieldSet!) repeatable on OBJECT | INTERFACE`},
+		{"requires", `directive @requires(fields: _FieldSet!) on FIELD_DEFINITION`},
+		{"provides", `directive @provides(fields: _FieldSet!) on FIELD_DEFINITION`},
+		{"extends", `directive @extends on OBJECT | INTERFACE`},
+		{"external", `directive @external on FIELD_DEFINITION`},
+		{"_Any", `scalar _Any`},
+		{"_FieldSet", `scalar _FieldSet`},
+	}
+}
+
+func federationV2Prelude() []definition {
+	return []definition{
+		{"authenticated", `directive @authenticated on FIELD_DEFINITION | OBJECT | INTERFACE | SCALAR | ENUM`},
+		{"composeDirective", `directive @composeDirective(name: String!) repeatable on SCHEMA`},
+		{"extends", `directive @extends on OBJECT | INTERFACE`},
+		{"external", `directive @external on OBJECT | FIELD_DEFINITION`},
+		{"key", `directive @key(fields: FieldSet!, resolvable: Boolean = true) repeatable on OBJECT | INTERFACE`},
+		{"inaccessible", `directive @inaccessible on ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | INPUT_OBJECT | INTERFACE | OBJECT | SCALAR | UNION`},
+		{"interfaceObject", `directive @interfaceObject on OBJECT`},
+		{"link", `directive @link(url: String!, as: String, import: [link__Import], for: link__Purpose) repeatable on SCHEMA`},
+		{"override", `directive @override(from: String!, label: String) on FIELD_DEFINITION`},
+		{"policy", `directive @policy(policies: [[federation__Policy!]!]!) on FIELD_DEFINITION | OBJECT | INTERFACE | SCALAR | ENUM`},
+		{"provides", `directive @provides(fields: FieldSet!) on FIELD_DEFINITION`},
+		{"requires", `directive @requires(fields: FieldSet!) on FIELD_DEFINITION`},
+		{"requiresScopes", `directive @requiresScopes(scopes: [[federation__Scope!]!]!) on FIELD_DEFINITION | OBJECT | INTERFACE | SCALAR | ENUM`},
+		{"shareable", `directive @shareable repeatable on FIELD_DEFINITION | OBJECT`},
+		{"tag", `directive @tag(name: String!) repeatable on ARGUMENT_DEFINITION | ENUM | ENUM_VALUE | FIELD_DEFINITION | INPUT_FIELD_DEFINITION | INPUT_OBJECT | INTERFACE | OBJECT | SCALAR | UNION`},
+		{"_Any", `scalar _Any`},
+		{"FieldSet", `scalar FieldSet`},
+		{"link__Import", `scalar link__Import`},
+		{"link__Purpose", `enum link__Purpose { SECURITY EXECUTION }`},
+		{"federation__Policy", `scalar federation__Policy`},
+		{"federation__Scope", `scalar federation__Scope`},
+	}
+}
+
+// DetectVersion returns the federation version the schema document opts into.
+// Federation 2 subgraphs link the federation specification using the @link directive on the schema.
+func DetectVersion(doc *ast.SchemaDocument) Version {
+	schemaDefinitions := append(ast.SchemaDefinitionList{}, doc.Schema...)
+	schemaDefinitions = append(schemaDefinitions, doc.SchemaExtension...)
+	for _, s := range schemaDefinitions {
+		for _, link := range s.Directives.ForNames("link") {
+			url := link.Arguments.ForName("url")
+			if url != nil && url.Value != nil && strings.Contains(url.Value.Raw, federationV2URL) {
+				return Version2
+			}
+		}
+	}
+	return Version1
+}
+
+// IsSubgraph reports whether the sources describe a federated subgraph,
+// i.e. they use federation directives without defining them.
+func IsSubgraph(sources ...*ast.Source) (bool, error) {
+	doc, err := parser.ParseSchemas(sources...)
+	if err != nil {
+		return false, err
+	}
+	prelude := map[string]bool{}
+	for _, d := range preludeFor(DetectVersion(doc)) {
+		prelude[d.name] = true
+	}
+	for name := range usedDirectives(doc) {
+		if prelude[name] && doc.Directives.ForName(name) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// LoadSchema loads the subgraph schema described by the sources.
+// The federation directives and scalars are injected, as well as the _Entity union
+// and the _entities and _service root query fields defined by the subgraph specification.
+func LoadSchema(sources ...*ast.Source) (*ast.Schema, error) {
+	doc, err := parser.ParseSchemas(sources...)
+	if err != nil {
+		return nil, err
+	}
+	early := preludeSource(doc)
+	sources = append(sources, early)
+	schema, err := gqlparser.LoadSchema(sources...)
+	if err != nil {
+		return nil, err
+	}
+	return gqlparser.LoadSchema(append(sources, entitySource(schema))...)
+}
+
+func preludeFor(version Version) []definition {
+	if version == Version2 {
+		return federationV2Prelude()
+	}
+	return federationV1Prelude()
+}
+
+func preludeSource(doc *ast.SchemaDocument) *ast.Source {
+	defined := map[string]bool{}
+	for _, d := range doc.Directives {
+		defined[d.Name] = true
+	}
+	for _, d := range doc.Definitions {
+		defined[d.Name] = true
+	}
+	input := []string{}
+	for _, d := range preludeFor(DetectVersion(doc)) {
+		if defined[d.name] {
+			continue
+		}
+		input = append(input, d.sdl)
+	}
+	return &ast.Source{
+		Name:    "federation/directives.graphql",
+		Input:   strings.Join(input, "\n"),
+		BuiltIn: true,
+	}
+}
+
+func entitySource(schema *ast.Schema) *ast.Source {
+	blocks := []string{}
+	queryFields := []string{}
+	entities := Entities(schema)
+	if len(entities) > 0 && schema.Types[EntityUnion] == nil {
+		names := make([]string, 0, len(entities))
+		for _, e := range entities {
+			names = append(names, e.Name)
+		}
+		blocks = append(blocks, fmt.Sprintf("union %s = %s", EntityUnion, strings.Join(names, " | ")))
+		if !hasQueryField(schema, EntitiesField) {
+			queryFields = append(queryFields, fmt.Sprintf("\t%s(representations: [_Any!]!): [%s]!", EntitiesField, EntityUnion))
+		}
+	}
+	if schema.Types["_Service"] == nil {
+		blocks = append(blocks, "type _Service {\n\tsdl: String\n}")
+	}
+	if !hasQueryField(schema, ServiceField) {
+		queryFields = append(queryFields, fmt.Sprintf("\t%s: _Service!", ServiceField))
+	}
+	if len(queryFields) > 0 {
+		// a subgraph without root query type still exposes the _service and _entities fields.
+		queryType := "type Query"
+		if schema.Query != nil {
+			queryType = "extend type " + schema.Query.Name
+		}
+		blocks = append(blocks, fmt.Sprintf("%s {\n%s\n}", queryType, strings.Join(queryFields, "\n")))
+	}
+	return &ast.Source{
+		Name:    "federation/entity.graphql",
+		Input:   strings.Join(blocks, "\n\n"),
+		BuiltIn: true,
+	}
+}
+
+func hasQueryField(schema *ast.Schema, name string) bool {
+	return schema.Query != nil && schema.Query.Fields.ForName(name) != nil
+}
+
+// Entities returns the object types which can be resolved through the _entities field,
+// i.e. all object types with a resolvable @key directive, sorted by name.
+func Entities(schema *ast.Schema) []*ast.Definition {
+	res := []*ast.Definition{}
+	for _, def := range schema.Types {
+		if def.Kind != ast.Object {
+			continue
+		}
+		if len(ResolvableKeys(def)) == 0 {
+			continue
+		}
+		res = append(res, def)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// ResolvableKeys returns the field sets of the @key directives of the definition,
+// excluding keys marked with resolvable: false.
+func ResolvableKeys(def *ast.Definition) []string {
+	res := []string{}
+	for _, key := range def.Directives.ForNames(KeyDirective) {
+		resolvable := key.Arguments.ForName("resolvable")
+		if resolvable != nil && resolvable.Value != nil && resolvable.Value.Raw == "false" {
+			continue
+		}
+		fields := key.Arguments.ForName("fields")
+		if fields == nil || fields.Value == nil {
+			continue
+		}
+		res = append(res, fields.Value.Raw)
+	}
+	return res
+}
+
+func usedDirectives(doc *ast.SchemaDocument) map[string]bool {
+	res := map[string]bool{}
+	add := func(directives ast.DirectiveList) {
+		for _, d := range directives {
+			res[d.Name] = true
+		}
+	}
+	for _, s := range append(append(ast.SchemaDefinitionList{}, doc.Schema...), doc.SchemaExtension...) {
+		add(s.Directives)
+	}
+	for _, def := range append(append(ast.DefinitionList{}, doc.Definitions...), doc.Extensions...) {
+		add(def.Directives)
+		for _, f := range def.Fields {
+			add(f.Directives)
+			for _, a := range f.Arguments {
+				add(a.Directives)
+			}
+		}
+		for _, v := range def.EnumValues {
+			add(v.Directives)
+		}
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestLoadSchemaVersion1(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Name: "reviews.graphql",
+		Input: `
+"A review, not to be confused with the @key directive."
+type Review @key(fields: "id") {
+  id: ID!
+  body: String!
+  author: User! @provides(fields: "username")
+}
+
+extend type User @key(fields: "id") {
+  id: ID! @external
+  username: String @external
+  reviews: [Review!]! @requires(fields: "username")
+}
+
+extend type Query {
+  reviews: [Review!]!
+}
+`,
+	}
+
+	isSubgraph, err := IsSubgraph(source)
+	require.NoError(t, err)
+	assert.True(t, isSubgraph)
+
+	schema, err := LoadSchema(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, "A review, not to be confused with the @key directive.", schema.Types["Review"].Description)
+	assert.Equal(t, []string{"Review", "User"}, schema.Types[EntityUnion].Types)
+	assert.NotNil(t, schema.Query.Fields.ForName("reviews"))
+	assert.Equal(t, "[_Entity]!", schema.Query.Fields.ForName(EntitiesField).Type.String())
+	assert.Equal(t, "_Service!", schema.Query.Fields.ForName(ServiceField).Type.String())
+	assert.NotNil(t, schema.Directives["requires"])
+	assert.NotNil(t, schema.Types["_FieldSet"])
+}
+
+func TestLoadSchemaVersion2(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Name: "products.graphql",
+		Input: `
+extend schema @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key", "@shareable"])
+
+type Product @key(fields: "upc") @key(fields: "sku variation { id }") {
+  upc: String!
+  sku: String!
+  variation: ProductVariation!
+  name: String @shareable
+}
+
+type ProductVariation {
+  id: ID!
+}
+
+type Category @key(fields: "id", resolvable: false) {
+  id: ID!
+}
+
+type Query {
+  topProducts(first: Int = 5): [Product]
+}
+`,
+	}
+
+	doc := mustParse(t, source)
+	assert.Equal(t, Version2, DetectVersion(doc))
+
+	schema, err := LoadSchema(source)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Product"}, schema.Types[EntityUnion].Types)
+	assert.Equal(t, []string{"upc", "sku variation { id }"}, ResolvableKeys(schema.Types["Product"]))
+	assert.Empty(t, ResolvableKeys(schema.Types["Category"]))
+	assert.NotNil(t, schema.Types["FieldSet"])
+	assert.NotNil(t, schema.Directives["shareable"])
+}
+
+func TestLoadSchemaWithoutEntities(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+type Query {
+  hello: String @shareable
+}
+
+extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@shareable"])
+`,
+	}
+	schema, err := LoadSchema(source)
+	require.NoError(t, err)
+	assert.Nil(t, schema.Types[EntityUnion])
+	assert.Nil(t, schema.Query.Fields.ForName(EntitiesField))
+	assert.NotNil(t, schema.Query.Fields.ForName(ServiceField))
+}
+
+func TestIsSubgraph(t *testing.T) {
+	t.Parallel()
+
+	isSubgraph, err := IsSubgraph(&ast.Source{
+		Input: `
+directive @key(fields: String!) on OBJECT
+
+type Book @key(fields: "id") {
+  id: ID!
+}
+
+type Query {
+  books: [Book!]!
+}
+`,
+	})
+	require.NoError(t, err)
+	assert.False(t, isSubgraph)
+
+	_, err = IsSubgraph(&ast.Source{Input: `type Query {`})
+	assert.Error(t, err)
+}
+
+func mustParse(t *testing.T, source *ast.Source) *ast.SchemaDocument {
+	t.Helper()
+	doc, err := parser.ParseSchemas(source)
+	require.NoError(t, err)
+	return doc
+}
@@ -4,14 +4,19 @@ package gen
 import (
 	"bytes"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"go/format"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"text/template"
 
+	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
 
 	"github.com/wimspaargaren/gql-gen-mcp/internal/tools"
 )
@@ -20,6 +25,12 @@ import (
 type Options struct {
 	// OutputDir is the directory where the generated files will be saved.
 	OutputDir string
+	// ToolOptions are passed along when converting the schema to tools.
+	ToolOptions []tools.Option
+	// BaseURL is the default URL of the GraphQL API used by the generated server.
+	BaseURL string
+	// ResourceName is the name used in the URIs of the resources, resources are only generated if it is set.
+	ResourceName string
 }
 
 func defaultGenOpts() *Options {
@@ -38,6 +49,28 @@ func WithOutputDir(dir string) Option {
 	}
 }
 
+// WithToolOptions sets the options used for converting the schema to tools.
+func WithToolOptions(options ...tools.Option) Option {
+	return func(opts *Options) {
+		opts.ToolOptions = append(opts.ToolOptions, options...)
+	}
+}
+
+// WithBaseURL sets the default URL of the GraphQL API used by the generated server.
+func WithBaseURL(url string) Option {
+	return func(opts *Options) {
+		opts.BaseURL = url
+	}
+}
+
+// WithResources generates MCP resources exposing the schema and resolving objects by their ID,
+// with URIs starting with graphql://name/.
+func WithResources(name string) Option {
+	return func(opts *Options) {
+		opts.ResourceName = name
+	}
+}
+
 //go:embed templates/tool-template.tmpl
 var toolTemplateContent string
 
@@ -46,31 +79,87 @@ var serverTemplateContent string
 
 // Generator is responsible for generating code based on the provided schema.
 type Generator struct {
-	tools   []tools.Tool
-	options *Options
+	schema    *ast.Schema
+	tools     []tools.Tool
+	resources []tools.Resource
+	options   *Options
 }
 
 // NewGenerator creates a new Generator instance with the provided schema.
-func NewGenerator(schema *ast.Schema, options ...Option) *Generator {
+// An error is returned if the tools cannot be generated for the schema.
+func NewGenerator(schema *ast.Schema, options ...Option) (*Generator, error) {
 	genOpts := defaultGenOpts()
 	for _, opt := range options {
 		opt(genOpts)
 	}
 
-	schemaTools := tools.GetToolsForSchema(schema)
+	schemaTools, err := tools.GetToolsForSchema(schema, genOpts.ToolOptions...)
+	if err != nil {
+		return nil, err
+	}
+	var resources []tools.Resource
+	if genOpts.ResourceName != "" {
+		resources, err = tools.GetResourcesForSchema(schema, genOpts.ToolOptions...)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &Generator{
-		tools:   schemaTools,
-		options: genOpts,
+		schema:    schema,
+		tools:     schemaTools,
+		resources: resources,
+		options:   genOpts,
+	}, nil
+}
+
+// Tools returns the tools which are generated for the schema.
+func (g *Generator) Tools() []tools.Tool {
+	return g.tools
+}
+
+// Resources returns the resources which are generated for the schema.
+func (g *Generator) Resources() []tools.Resource {
+	return g.resources
+}
+
+// Validate validates the query of every tool and resource against the schema, such that invalid queries are
+// reported when generating instead of when the tool is called. The errors are joined using errors.Join.
+func (g *Generator) Validate() error {
+	errs := []error{}
+	for _, tool := range g.tools {
+		_, list := gqlparser.LoadQuery(g.schema, tool.Query)
+		for _, err := range list {
+			errs = append(errs, fmt.Errorf("tool %s: invalid query: %w", tool.Name, err))
+		}
+	}
+	for _, resource := range g.resources {
+		_, list := gqlparser.LoadQuery(g.schema, resource.Query)
+		for _, err := range list {
+			errs = append(errs, fmt.Errorf("resource %s: invalid query: %w", resource.TypeName, err))
+		}
 	}
+	return errors.Join(errs...)
 }
 
-// Generate generates the code based on the provided schema and options.
+// Generate validates the queries of the tools and generates the code based on the provided schema and options.
 func (g *Generator) Generate() error {
+	err := g.Validate()
+	if err != nil {
+		return fmt.Errorf("error validating queries: %w", err)
+	}
 	data := TemplateData{
-		Tools: g.tools,
+		Tools:     g.tools,
+		Resources: g.resources,
+	}
+	if g.options.ResourceName != "" {
+		data.ResourceURI = "graphql://" + g.options.ResourceName
+		data.SDL = schemaSDL(g.schema)
+	}
+	for _, tool := range g.tools {
+		data.Subscriptions = data.Subscriptions || tool.OperationType == tools.SubscriptionResolver
 	}
 
-	err := g.generateTools(data)
+	err = g.generateTools(data)
 	if err != nil {
 		return fmt.Errorf("error generating tools: %w", err)
 	}
@@ -84,6 +173,18 @@ func (g *Generator) Generate() error {
 // TemplateData represents the data structure used in the template.
 type TemplateData struct {
 	Tools []tools.Tool
+	// Subscriptions is set if any of the tools executes a subscription.
+	Subscriptions bool
+	// ResourceURI is the prefix of the URIs of the resources, resources are generated if it is set.
+	ResourceURI string
+	Resources   []tools.Resource
+	// SDL is the schema exposed as resource.
+	SDL string
+}
+
+// ServerTemplateData represents the data structure used in the server template.
+type ServerTemplateData struct {
+	BaseURL string
 }
 
 func (g *Generator) generateTools(data TemplateData) error {
@@ -94,6 +195,14 @@ func (g *Generator) generateTools(data TemplateData) error {
 			}
 			return strings.ToUpper(string(s[0])) + s[1:]
 		},
+		"inputSchema": func(tool tools.Tool) (string, error) {
+			b, err := json.Marshal(tool.InputSchema())
+			if err != nil {
+				return "", fmt.Errorf("error marshalling input schema of tool %s: %w", tool.Name, err)
+			}
+			return stringLiteral(string(b)), nil
+		},
+		"stringLiteral": stringLiteral,
 	}
 	tpl, err := template.New("mcp-tool-gql").Funcs(funcMap).Parse(toolTemplateContent)
 	if err != nil {
@@ -109,6 +218,29 @@ func (g *Generator) generateTools(data TemplateData) error {
 	return g.writeFile(buf, "tools")
 }
 
+// schemaSDL returns the SDL of the schema, without the definitions of the tool directives added by
+// tools.Prelude as these are not part of the schema of the API.
+func schemaSDL(schema *ast.Schema) string {
+	s := *schema
+	s.Directives = map[string]*ast.DirectiveDefinition{}
+	for name, d := range schema.Directives {
+		if d.Position == nil || d.Position.Src == nil || d.Position.Src.Name != tools.PreludeName {
+			s.Directives[name] = d
+		}
+	}
+	var sdl strings.Builder
+	formatter.NewFormatter(&sdl).FormatSchema(&s)
+	return sdl.String()
+}
+
+// stringLiteral returns the string as Go raw string literal, or as interpreted string literal if it contains a backtick.
+func stringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
 func (g *Generator) generateServer() error {
 	fileExists := fileExists(fmt.Sprintf("%s/main.go", g.options.OutputDir))
 	if fileExists {
@@ -121,7 +253,9 @@ func (g *Generator) generateServer() error {
 
 	// Create a buffer to hold the template output
 	var buf bytes.Buffer
-	err = tpl.Execute(&buf, g.tools)
+	err = tpl.Execute(&buf, ServerTemplateData{
+		BaseURL: g.options.BaseURL,
+	})
 	if err != nil {
 		return fmt.Errorf("error executing template: %w", err)
 	}
@@ -0,0 +1,139 @@
+package gen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/federation"
+	"github.com/wimspaargaren/gql-gen-mcp/internal/tools"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	schema, err := federation.LoadSchema(&ast.Source{
+		Input: `
+type Book @key(fields: "id") {
+  id: ID!
+  title: String!
+}
+
+input BookFilter {
+  title: String
+}
+
+type Query {
+  books(filter: BookFilter, first: Int! = 10): [Book!]!
+}`,
+	})
+	assert.NoError(t, err)
+
+	generator, err := NewGenerator(schema, WithToolOptions(tools.WithEntityTools()))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(generator.Tools()))
+	assert.NoError(t, generator.Validate())
+
+	generator.tools = append(generator.tools, tools.Tool{
+		Name:  "invalid",
+		Query: "query invalid { books { isbn } }",
+	})
+	err = generator.Validate()
+	assert.EqualError(t, err, `tool invalid: invalid query: input:1: Cannot query field "isbn" on type "Book".`)
+	assert.ErrorContains(t, generator.Generate(), "error validating queries")
+}
+
+func TestGenerateEscapesDescriptions(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+"A \"book\" in the store."
+type Book {
+  id: ID!
+}
+
+type Query {
+  book(id: ID!): Book @mcpTool(description: "Find a \"book\" by id")
+  books: [Book!]! @deprecated(reason: """
+Use "book"
+instead.
+""")
+}`,
+	}
+	prelude, err := tools.Prelude(source)
+	assert.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+
+	output := t.TempDir()
+	generator, err := NewGenerator(schema, WithOutputDir(output), WithResources("store"),
+		WithToolOptions(tools.WithDeprecated(tools.DeprecatedMark)))
+	assert.NoError(t, err)
+	assert.NoError(t, generator.Generate())
+
+	b, err := os.ReadFile(filepath.Join(output, "tools.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `mcp.NewToolWithRawSchema("book", "Find a \"book\" by id",`)
+	assert.Contains(t, string(b), `mcp.NewToolWithRawSchema("books", "Deprecated: Use \"book\" instead.",`)
+	assert.Contains(t, string(b), `mcp.WithTemplateDescription("Book by its id. A \"book\" in the store."),`)
+}
+
+func TestGenerateSchemaResource(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+type Book {
+  id: ID!
+}
+
+type Query {
+  book(id: ID!): Book @mcpTool(name: "findBook")
+}`,
+	}
+	prelude, err := tools.Prelude(source)
+	assert.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+
+	output := t.TempDir()
+	generator, err := NewGenerator(schema, WithOutputDir(output), WithResources("store"))
+	assert.NoError(t, err)
+	assert.NoError(t, generator.Generate())
+
+	b, err := os.ReadFile(filepath.Join(output, "tools.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), "type Book {")
+	assert.NotContains(t, string(b), "directive @mcpTool")
+	assert.NotContains(t, string(b), "directive @mcpArg")
+}
+
+func TestGenerateEscapesQueries(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Book {
+  id: ID!
+}
+
+type Query {
+  book(id: ID!, q: String = "a` + "`" + `b"): Book
+}`,
+	})
+	assert.NoError(t, err)
+
+	output := t.TempDir()
+	generator, err := NewGenerator(schema, WithOutputDir(output), WithResources("store"))
+	assert.NoError(t, err)
+	assert.NoError(t, generator.Generate())
+
+	b, err := os.ReadFile(filepath.Join(output, "tools.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `$q: String = \"a`+"`"+`b\"`)
+}
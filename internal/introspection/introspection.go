@@ -0,0 +1,239 @@
+// Package introspection converts the result of a GraphQL introspection query into a GraphQL AST schema.
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/wimspaargaren/gql-gen-mcp/graphql"
+)
+
+// Query is the standard introspection query used to retrieve the full schema of a GraphQL API.
+const Query = `
+query IntrospectionQuery {
+	__schema {
+		queryType { name }
+		mutationType { name }
+		subscriptionType { name }
+		types {
+			...FullType
+		}
+		directives {
+			name
+			description
+			locations
+			args {
+				...InputValue
+			}
+		}
+	}
+}
+
+fragment FullType on __Type {
+	kind
+	name
+	description
+	fields(includeDeprecated: true) {
+		name
+		description
+		args {
+			...InputValue
+		}
+		type {
+			...TypeRef
+		}
+		isDeprecated
+		deprecationReason
+	}
+	inputFields {
+		...InputValue
+	}
+	interfaces {
+		...TypeRef
+	}
+	enumValues(includeDeprecated: true) {
+		name
+		description
+		isDeprecated
+		deprecationReason
+	}
+	possibleTypes {
+		...TypeRef
+	}
+}
+
+fragment InputValue on __InputValue {
+	name
+	description
+	type {
+		...TypeRef
+	}
+	defaultValue
+}
+
+fragment TypeRef on __Type {
+	kind
+	name
+	ofType {
+		kind
+		name
+		ofType {
+			kind
+			name
+			ofType {
+				kind
+				name
+				ofType {
+					kind
+					name
+					ofType {
+						kind
+						name
+						ofType {
+							kind
+							name
+							ofType {
+								kind
+								name
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+`
+
+// Result represents the data returned by the introspection query.
+type Result struct {
+	Schema Schema `json:"__schema"`
+}
+
+// Schema represents the __schema field of an introspection result.
+type Schema struct {
+	QueryType        *TypeName   `json:"queryType"`
+	MutationType     *TypeName   `json:"mutationType"`
+	SubscriptionType *TypeName   `json:"subscriptionType"`
+	Types            []FullType  `json:"types"`
+	Directives       []Directive `json:"directives"`
+}
+
+// TypeName represents a reference to a named type.
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+// FullType represents a type definition in an introspection result.
+type FullType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+// Field represents a field of an object or interface type.
+type Field struct {
+	Name              string       `json:"name"`
+	Description       string       `json:"description"`
+	Args              []InputValue `json:"args"`
+	Type              TypeRef      `json:"type"`
+	IsDeprecated      bool         `json:"isDeprecated"`
+	DeprecationReason *string      `json:"deprecationReason"`
+}
+
+// InputValue represents an argument or an input object field.
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+// EnumValue represents a value of an enum type.
+type EnumValue struct {
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+// TypeRef represents a (possibly wrapped) reference to a type.
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// Directive represents a directive definition.
+type Directive struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Locations   []string     `json:"locations"`
+	Args        []InputValue `json:"args"`
+}
+
+// Fetch executes the introspection query against the GraphQL API at the given URL.
+func Fetch(ctx context.Context, url string, headers map[string]string) (*Schema, error) {
+	client := graphql.NewDefaultClient(url, func(req *http.Request) error {
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return nil
+	})
+	result := Result{}
+	err := client.Call(ctx, graphql.Request{
+		Query:         Query,
+		OperationName: "IntrospectionQuery",
+	}, &result)
+	if err != nil {
+		return nil, fmt.Errorf("introspection query failed: %w", err)
+	}
+	return &result.Schema, nil
+}
+
+// Parse parses an introspection result as stored on disk, e.g. a schema.json file.
+// Both the full GraphQL response ({"data": {"__schema": ...}}) and the bare data ({"__schema": ...}) are accepted.
+func Parse(data []byte) (*Schema, error) {
+	response := struct {
+		Data *Result `json:"data"`
+		*Result
+	}{
+		Result: &Result{},
+	}
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal introspection result failed: %w", err)
+	}
+	result := response.Result
+	if response.Data != nil {
+		result = response.Data
+	}
+	if result.Schema.QueryType == nil && len(result.Schema.Types) == 0 {
+		return nil, errors.New("introspection result does not contain a __schema")
+	}
+	return &result.Schema, nil
+}
+
+// BuildSchema converts the introspection schema into a GraphQL AST schema.
+// The name is used as source name in parser errors.
+func BuildSchema(schema *Schema, name string) (*ast.Schema, error) {
+	gqlSchema, err := gqlparser.LoadSchema(&ast.Source{
+		Name:  name,
+		Input: schema.SDL(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing introspection schema: %w", err)
+	}
+	return gqlSchema, nil
+}
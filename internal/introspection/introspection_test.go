@@ -0,0 +1,192 @@
+package introspection
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/tools"
+)
+
+func introspectionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	payload, err := os.ReadFile("testdata/introspection.json")
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "unauthorized"}]}`))
+			return
+		}
+		body := map[string]any{}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil || body["query"] != Query {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "unexpected query"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payload)
+	}))
+}
+
+func TestFetchAndBuildSchema(t *testing.T) {
+	t.Parallel()
+
+	server := introspectionServer(t)
+	defer server.Close()
+
+	introspectionSchema, err := Fetch(t.Context(), server.URL, map[string]string{"Authorization": "Bearer secret"})
+	require.NoError(t, err)
+
+	schema, err := BuildSchema(introspectionSchema, server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Query", schema.Query.Name)
+	assert.Equal(t, "Mutation", schema.Mutation.Name)
+	assert.Nil(t, schema.Subscription)
+
+	book := schema.Types["Book"]
+	require.NotNil(t, book)
+	assert.Equal(t, ast.Object, book.Kind)
+	assert.Equal(t, "Represents a book in the store.", book.Description)
+	assert.Equal(t, []string{"Node"}, book.Interfaces)
+	assert.Equal(t, "Author!", book.Fields.ForName("author").Type.String())
+
+	listInput := schema.Types["BookListInput"]
+	require.NotNil(t, listInput)
+	assert.Equal(t, ast.InputObject, listInput.Kind)
+	assert.Equal(t, "10", listInput.Fields.ForName("first").DefaultValue.String())
+
+	genre := schema.Types["Genre"]
+	require.NotNil(t, genre)
+	assert.Equal(t, ast.Enum, genre.Kind)
+	assert.Len(t, genre.EnumValues, 11)
+
+	schemaTools, err := tools.GetToolsForSchema(schema)
+	require.NoError(t, err)
+	assert.Len(t, schemaTools, 7)
+}
+
+func TestFetchUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	server := introspectionServer(t)
+	defer server.Close()
+
+	_, err := Fetch(t.Context(), server.URL, nil)
+	assert.ErrorContains(t, err, "unauthorized")
+}
+
+func TestSDL(t *testing.T) {
+	t.Parallel()
+
+	reason := `use "other" instead`
+	defaultValue := "ASC"
+	schema := &Schema{
+		QueryType: &TypeName{Name: "Query"},
+		Types: []FullType{
+			{Kind: kindScalar, Name: "String"},
+			{Kind: kindObject, Name: "__Schema"},
+			{
+				Kind:        kindObject,
+				Name:        "Query",
+				Description: `Contains """quotes"""`,
+				Fields: []Field{
+					{
+						Name: "items",
+						Args: []InputValue{
+							{
+								Name:         "order",
+								Type:         TypeRef{Kind: kindEnum, Name: "Order"},
+								DefaultValue: &defaultValue,
+							},
+						},
+						Type: TypeRef{Kind: kindNonNull, OfType: &TypeRef{Kind: kindList, OfType: &TypeRef{
+							Kind: kindNonNull, OfType: &TypeRef{Kind: kindScalar, Name: "String"},
+						}}},
+						IsDeprecated:      true,
+						DeprecationReason: &reason,
+					},
+				},
+			},
+			{
+				Kind:       kindEnum,
+				Name:       "Order",
+				EnumValues: []EnumValue{{Name: "ASC"}, {Name: "DESC"}},
+			},
+		},
+	}
+
+	expected := `schema {
+	query: Query
+}
+
+"""
+Contains \"""quotes\"""
+"""
+type Query {
+	items(
+		order: Order = ASC
+	): [String!]! @deprecated(reason: "use \"other\" instead")
+}
+
+enum Order {
+	ASC
+	DESC
+}
+
+`
+	assert.Equal(t, expected, schema.SDL())
+
+	gqlSchema, err := BuildSchema(schema, "test")
+	require.NoError(t, err)
+	assert.Equal(t, `Contains """quotes"""`, gqlSchema.Query.Description)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	payload, err := os.ReadFile("testdata/introspection.json")
+	require.NoError(t, err)
+
+	response := map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal(payload, &response))
+
+	tests := []struct {
+		Name string
+		Data []byte
+	}{
+		{Name: "full response", Data: payload},
+		{Name: "bare data", Data: response["data"]},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			t.Parallel()
+
+			introspectionSchema, err := Parse(test.Data)
+			require.NoError(t, err)
+			schema, err := BuildSchema(introspectionSchema, "schema.json")
+			require.NoError(t, err)
+			assert.Equal(t, "Query", schema.Query.Name)
+			assert.NotNil(t, schema.Types["BookConnection"])
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse([]byte(`{"data": {"books": []}}`))
+	assert.ErrorContains(t, err, "does not contain a __schema")
+
+	_, err = Parse([]byte(`not json`))
+	assert.ErrorContains(t, err, "unmarshal introspection result failed")
+}
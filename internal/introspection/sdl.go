@@ -0,0 +1,207 @@
+package introspection
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Type kinds as returned by the __TypeKind enum.
+const (
+	kindScalar      = "SCALAR"
+	kindObject      = "OBJECT"
+	kindInterface   = "INTERFACE"
+	kindUnion       = "UNION"
+	kindEnum        = "ENUM"
+	kindInputObject = "INPUT_OBJECT"
+	kindList        = "LIST"
+	kindNonNull     = "NON_NULL"
+)
+
+func isBuiltInScalar(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	default:
+		return false
+	}
+}
+
+func isBuiltInDirective(name string) bool {
+	switch name {
+	case "include", "skip", "deprecated", "specifiedBy", "defer", "oneOf":
+		return true
+	default:
+		return false
+	}
+}
+
+// SDL prints the introspection schema in the GraphQL schema definition language.
+// Built-in scalars, directives and introspection types are omitted, as they are part of the parser prelude.
+func (s *Schema) SDL() string {
+	b := &strings.Builder{}
+	writeSchemaDefinition(b, s)
+	for _, d := range s.Directives {
+		if isBuiltInDirective(d.Name) {
+			continue
+		}
+		writeDirective(b, d)
+	}
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || isBuiltInScalar(t.Name) {
+			continue
+		}
+		writeType(b, t)
+	}
+	return b.String()
+}
+
+func writeSchemaDefinition(b *strings.Builder, s *Schema) {
+	b.WriteString("schema {\n")
+	if s.QueryType != nil {
+		b.WriteString("\tquery: " + s.QueryType.Name + "\n")
+	}
+	if s.MutationType != nil {
+		b.WriteString("\tmutation: " + s.MutationType.Name + "\n")
+	}
+	if s.SubscriptionType != nil {
+		b.WriteString("\tsubscription: " + s.SubscriptionType.Name + "\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+func writeDirective(b *strings.Builder, d Directive) {
+	writeDescription(b, d.Description, "")
+	b.WriteString("directive @" + d.Name)
+	writeArgs(b, d.Args, "")
+	b.WriteString(" on " + strings.Join(d.Locations, " | ") + "\n\n")
+}
+
+func writeType(b *strings.Builder, t FullType) {
+	writeDescription(b, t.Description, "")
+	switch t.Kind {
+	case kindScalar:
+		b.WriteString("scalar " + t.Name + "\n\n")
+	case kindObject, kindInterface:
+		keyword := "type"
+		if t.Kind == kindInterface {
+			keyword = "interface"
+		}
+		b.WriteString(keyword + " " + t.Name)
+		writeImplements(b, t.Interfaces)
+		b.WriteString(" {\n")
+		for _, f := range t.Fields {
+			writeDescription(b, f.Description, "\t")
+			b.WriteString("\t" + f.Name)
+			writeArgs(b, f.Args, "\t")
+			b.WriteString(": " + typeRefString(f.Type))
+			writeDeprecated(b, f.IsDeprecated, f.DeprecationReason)
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n\n")
+	case kindUnion:
+		names := make([]string, 0, len(t.PossibleTypes))
+		for _, p := range t.PossibleTypes {
+			names = append(names, p.Name)
+		}
+		b.WriteString("union " + t.Name + " = " + strings.Join(names, " | ") + "\n\n")
+	case kindEnum:
+		b.WriteString("enum " + t.Name + " {\n")
+		for _, v := range t.EnumValues {
+			writeDescription(b, v.Description, "\t")
+			b.WriteString("\t" + v.Name)
+			writeDeprecated(b, v.IsDeprecated, v.DeprecationReason)
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n\n")
+	case kindInputObject:
+		b.WriteString("input " + t.Name + " {\n")
+		for _, f := range t.InputFields {
+			writeDescription(b, f.Description, "\t")
+			b.WriteString("\t")
+			writeInputValue(b, f)
+			b.WriteString("\n")
+		}
+		b.WriteString("}\n\n")
+	}
+}
+
+func writeImplements(b *strings.Builder, interfaces []TypeRef) {
+	if len(interfaces) == 0 {
+		return
+	}
+	names := make([]string, 0, len(interfaces))
+	for _, i := range interfaces {
+		names = append(names, i.Name)
+	}
+	b.WriteString(" implements " + strings.Join(names, " & "))
+}
+
+func writeArgs(b *strings.Builder, args []InputValue, indent string) {
+	if len(args) == 0 {
+		return
+	}
+	b.WriteString("(\n")
+	for _, a := range args {
+		writeDescription(b, a.Description, indent+"\t")
+		b.WriteString(indent + "\t")
+		writeInputValue(b, a)
+		b.WriteString("\n")
+	}
+	b.WriteString(indent + ")")
+}
+
+func writeInputValue(b *strings.Builder, v InputValue) {
+	b.WriteString(v.Name + ": " + typeRefString(v.Type))
+	if v.DefaultValue != nil {
+		b.WriteString(" = " + *v.DefaultValue)
+	}
+}
+
+func writeDeprecated(b *strings.Builder, isDeprecated bool, reason *string) {
+	if !isDeprecated {
+		return
+	}
+	if reason == nil {
+		b.WriteString(" @deprecated")
+		return
+	}
+	b.WriteString(" @deprecated(reason: " + quote(*reason) + ")")
+}
+
+func writeDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(strings.ReplaceAll(description, `"""`, `\"""`), "\n") {
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func typeRefString(t TypeRef) string {
+	switch t.Kind {
+	case kindNonNull:
+		if t.OfType == nil {
+			return ""
+		}
+		return typeRefString(*t.OfType) + "!"
+	case kindList:
+		if t.OfType == nil {
+			return ""
+		}
+		return "[" + typeRefString(*t.OfType) + "]"
+	default:
+		return t.Name
+	}
+}
+
+// quote returns s as a GraphQL string literal. JSON string escaping is a subset of GraphQL string escaping.
+func quote(s string) string {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,162 @@
+// Package source resolves GraphQL schema files and loads them as parser sources.
+package source
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// DirPatterns returns the glob patterns matching all .graphql and .graphqls
+// schema files in the directory and its subdirectories.
+func DirPatterns(dir string) []string {
+	dir = filepath.ToSlash(dir)
+	return []string{
+		path.Join(dir, "**", "*.graphql"),
+		path.Join(dir, "**", "*.graphqls"),
+	}
+}
+
+// IsPattern reports whether the path contains glob meta characters.
+func IsPattern(p string) bool {
+	return strings.ContainsAny(p, `*?[\`)
+}
+
+// Glob returns the files matching the patterns, sorted and without duplicates.
+// Patterns use the syntax of path.Match, in addition a ** path segment matches
+// zero or more directories, e.g. schema/**/*.graphqls.
+func Glob(patterns ...string) ([]string, error) {
+	seen := map[string]bool{}
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if seen[m] {
+				continue
+			}
+			seen[m] = true
+			files = append(files, m)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func glob(pattern string) ([]string, error) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	for _, s := range segments {
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+	}
+
+	literal := 0
+	for literal < len(segments) && !IsPattern(segments[literal]) {
+		literal++
+	}
+	if literal == len(segments) {
+		return existingFile(filepath.FromSlash(pattern))
+	}
+	base := strings.Join(segments[:literal], "/")
+	switch {
+	case base == "" && literal > 0:
+		base = "/"
+	case base == "":
+		base = "."
+	}
+	base = filepath.FromSlash(base)
+
+	files := []string{}
+	err := filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		if match(segments[literal:], strings.Split(filepath.ToSlash(rel), "/")) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while reading directory: %s %w", base, err)
+	}
+	return files, nil
+}
+
+func existingFile(name string) ([]string, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %s %w", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, use a pattern such as %s", name, DirPatterns(name)[0])
+	}
+	return []string{name}, nil
+}
+
+// match reports whether the path segments match the pattern segments.
+func match(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if match(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// Load reads the files into sources which are named after the file path, such
+// that parser errors refer to the file in which they occur.
+func Load(files ...string) ([]*ast.Source, error) {
+	sources := make([]*ast.Source, 0, len(files))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %s %w", file, err)
+		}
+		sources = append(sources, &ast.Source{
+			Name:  file,
+			Input: string(b),
+		})
+	}
+	return sources, nil
+}
+
+// LoadGlob loads the files matching the patterns, an error is returned if no file matches.
+func LoadGlob(patterns ...string) ([]*ast.Source, error) {
+	files, err := Glob(patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schema files found matching %s", strings.Join(patterns, ", "))
+	}
+	return Load(files...)
+}
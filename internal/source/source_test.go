@@ -0,0 +1,91 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		p := filepath.Join(dir, f)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+		require.NoError(t, os.WriteFile(p, []byte("type "+filepath.Base(filepath.Dir(p))+" { id: ID }"), 0o600))
+	}
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"schema.graphql",
+		"b/book.graphqls",
+		"a/author.graphqls",
+		"a/nested/review.graphqls",
+		"a/README.md",
+	)
+
+	files, err := Glob(filepath.Join(dir, "**", "*.graphqls"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a", "author.graphqls"),
+		filepath.Join(dir, "a", "nested", "review.graphqls"),
+		filepath.Join(dir, "b", "book.graphqls"),
+	}, files)
+
+	files, err = Glob(filepath.Join(dir, "a", "*.graphqls"), filepath.Join(dir, "a", "**", "*.graphqls"), filepath.Join(dir, "schema.graphql"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a", "author.graphqls"),
+		filepath.Join(dir, "a", "nested", "review.graphqls"),
+		filepath.Join(dir, "schema.graphql"),
+	}, files)
+
+	files, err = Glob(DirPatterns(dir)...)
+	require.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	files, err = Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestGlobErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := Glob(filepath.Join(dir, "[*.graphql"))
+	assert.ErrorContains(t, err, "invalid pattern")
+
+	_, err = Glob(filepath.Join(dir, "missing.graphql"))
+	assert.ErrorContains(t, err, "error reading file")
+
+	_, err = Glob(dir)
+	assert.ErrorContains(t, err, "is a directory")
+
+	_, err = Glob(filepath.Join(dir, "missing", "*.graphql"))
+	assert.ErrorContains(t, err, "error while reading directory")
+}
+
+func TestLoadGlob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, "b/book.graphql", "a/author.graphql")
+
+	sources, err := LoadGlob(DirPatterns(dir)...)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, filepath.Join(dir, "a", "author.graphql"), sources[0].Name)
+	assert.Equal(t, "type a { id: ID }", sources[0].Input)
+	assert.Equal(t, filepath.Join(dir, "b", "book.graphql"), sources[1].Name)
+
+	_, err = LoadGlob(filepath.Join(dir, "*.graphqls"))
+	assert.ErrorContains(t, err, "no schema files found")
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// cyclicTypes returns the types which can reach themselves through their fields, i.e. the types of
+// the strongly connected components of the type graph which contain a cycle. The graph is built from
+// the fields of object and interface types and the members of unions.
+func cyclicTypes(schema *ast.Schema) map[string]bool {
+	names := []string{}
+	for name := range schema.Types {
+		if !strings.HasPrefix(name, "__") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	t := &tarjan{
+		schema:  schema,
+		index:   map[string]int{},
+		lowLink: map[string]int{},
+		onStack: map[string]bool{},
+		cyclic:  map[string]bool{},
+	}
+	for _, name := range names {
+		if _, ok := t.index[name]; !ok {
+			t.connect(name)
+		}
+	}
+	return t.cyclic
+}
+
+// typeEdges returns the types which are directly reachable from the type, which for
+// interfaces includes the implementing types.
+func typeEdges(schema *ast.Schema, def *ast.Definition) []string {
+	res := []string{}
+	switch def.Kind {
+	case ast.Object, ast.Interface:
+		for _, f := range def.Fields {
+			res = append(res, f.Type.Name())
+		}
+		if def.Kind == ast.Interface {
+			for _, possibleType := range schema.GetPossibleTypes(def) {
+				res = append(res, possibleType.Name)
+			}
+		}
+	case ast.Union:
+		res = append(res, def.Types...)
+	}
+	return res
+}
+
+// tarjan finds the strongly connected components of the type graph using Tarjan's algorithm.
+type tarjan struct {
+	schema  *ast.Schema
+	counter int
+	index   map[string]int
+	lowLink map[string]int
+	stack   []string
+	onStack map[string]bool
+	cyclic  map[string]bool
+}
+
+func (t *tarjan) connect(name string) {
+	t.index[name] = t.counter
+	t.lowLink[name] = t.counter
+	t.counter++
+	t.stack = append(t.stack, name)
+	t.onStack[name] = true
+
+	selfReference := false
+	for _, edge := range typeEdges(t.schema, t.schema.Types[name]) {
+		if _, ok := t.schema.Types[edge]; !ok || strings.HasPrefix(edge, "__") {
+			continue
+		}
+		selfReference = selfReference || edge == name
+		if _, ok := t.index[edge]; !ok {
+			t.connect(edge)
+			t.lowLink[name] = min(t.lowLink[name], t.lowLink[edge])
+		} else if t.onStack[edge] {
+			t.lowLink[name] = min(t.lowLink[name], t.index[edge])
+		}
+	}
+
+	if t.lowLink[name] != t.index[name] {
+		return
+	}
+	component := []string{}
+	for {
+		last := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[last] = false
+		component = append(component, last)
+		if last == name {
+			break
+		}
+	}
+	if len(component) > 1 || selfReference {
+		for _, c := range component {
+			t.cyclic[c] = true
+		}
+	}
+}
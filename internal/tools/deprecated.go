@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Deprecated configures how operations, arguments, input fields, response fields and
+// enum values marked with @deprecated are handled.
+type Deprecated string
+
+const (
+	// DeprecatedInclude treats deprecated items like any other item.
+	DeprecatedInclude Deprecated = "include"
+	// DeprecatedMark includes deprecated items, marking them as deprecated and appending
+	// the reason to their description.
+	DeprecatedMark Deprecated = "mark"
+	// DeprecatedExclude leaves out deprecated items. Deprecated arguments and input fields
+	// are only left out if they are optional.
+	DeprecatedExclude Deprecated = "exclude"
+)
+
+// ParseDeprecated parses the handling of deprecated items, which defaults to DeprecatedInclude.
+func ParseDeprecated(s string) (Deprecated, error) {
+	switch Deprecated(strings.ToLower(s)) {
+	case "", DeprecatedInclude:
+		return DeprecatedInclude, nil
+	case DeprecatedMark:
+		return DeprecatedMark, nil
+	case DeprecatedExclude:
+		return DeprecatedExclude, nil
+	default:
+		return "", fmt.Errorf("unsupported value %s, use include, mark or exclude", s)
+	}
+}
+
+// deprecationReason returns the reason of the @deprecated directive, if present.
+func deprecationReason(directives ast.DirectiveList) (string, bool) {
+	d := directives.ForName("deprecated")
+	if d == nil {
+		return "", false
+	}
+	reason := directiveArgument(d, "reason")
+	if reason == "" {
+		reason = "No longer supported"
+	}
+	return strings.ReplaceAll(reason, "\n", " "), true
+}
+
+// excluded reports whether the item with the given directives is left out as it's deprecated.
+func (s *Schema) excluded(directives ast.DirectiveList) bool {
+	_, deprecated := deprecationReason(directives)
+	return deprecated && s.deprecated == DeprecatedExclude
+}
+
+// excludedArg reports whether the argument or input field is left out as it's deprecated.
+func (s *Schema) excludedArg(t *ast.Type, defaultValue *ast.Value, directives ast.DirectiveList) bool {
+	return !isRequired(t, defaultValue) && s.excluded(directives)
+}
+
+// markDeprecated appends the deprecation reason to the description if deprecated items are
+// marked, it reports whether the item is marked as deprecated.
+func (s *Schema) markDeprecated(description string, directives ast.DirectiveList) (string, bool) {
+	reason, deprecated := deprecationReason(directives)
+	if !deprecated || s.deprecated != DeprecatedMark {
+		return description, false
+	}
+	return joinDescriptions(description, "Deprecated: "+strings.ReplaceAll(reason, "\n", " ")), true
+}
+
+// joinDescriptions joins the non-empty descriptions.
+func joinDescriptions(descriptions ...string) string {
+	res := []string{}
+	for _, d := range descriptions {
+		if d != "" {
+			res = append(res, d)
+		}
+	}
+	return strings.Join(res, " ")
+}
@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Directives to customize the generated tools in the schema.
+const (
+	// ToolDirective customizes the tool generated for a query or mutation field.
+	ToolDirective = "mcpTool"
+	// ArgDirective customizes a tool argument or input field.
+	ArgDirective = "mcpArg"
+)
+
+// PreludeName is the name of the source returned by Prelude.
+const PreludeName = "mcp/directives.graphql"
+
+type directiveDefinition struct {
+	name string
+	sdl  string
+}
+
+func directiveDefinitions() []directiveDefinition {
+	return []directiveDefinition{
+		{
+			name: ToolDirective,
+			sdl: `"Customizes the MCP tool generated for a query or mutation field."
+directive @mcpTool(
+  "Name of the tool, defaults to the name of the field."
+  name: String
+  "Description of the tool, defaults to the description of the field."
+  description: String
+  "Hides the field, no tool is generated for it."
+  hidden: Boolean = false
+) on FIELD_DEFINITION`,
+		},
+		{
+			name: ArgDirective,
+			sdl: `"Customizes a tool argument or input field."
+directive @mcpArg(
+  "Description of the argument, defaults to the description of the argument."
+  description: String
+) on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION`,
+		},
+	}
+}
+
+// Prelude returns a source defining the @mcpTool and @mcpArg directives which
+// are not defined by the sources themselves, such that schemas can use them
+// without declaring them. It returns nil if all directives are defined.
+func Prelude(sources ...*ast.Source) (*ast.Source, error) {
+	doc, err := parser.ParseSchemas(sources...)
+	if err != nil {
+		return nil, err
+	}
+	defs := []string{}
+	for _, d := range directiveDefinitions() {
+		if doc.Directives.ForName(d.name) != nil {
+			continue
+		}
+		defs = append(defs, d.sdl)
+	}
+	if len(defs) == 0 {
+		return nil, nil
+	}
+	return &ast.Source{
+		Name:  PreludeName,
+		Input: strings.Join(defs, "\n\n"),
+	}, nil
+}
+
+var nameRegexp = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// toolDirective holds the values of the @mcpTool directive of a field.
+type toolDirective struct {
+	name        string
+	description string
+	hidden      bool
+}
+
+func toolDirectiveForField(v *ast.FieldDefinition) (toolDirective, error) {
+	res := toolDirective{
+		name:        v.Name,
+		description: v.Description,
+	}
+	d := v.Directives.ForName(ToolDirective)
+	if d == nil {
+		return res, nil
+	}
+	if name := directiveArgument(d, "name"); name != "" {
+		if !nameRegexp.MatchString(name) {
+			return res, fmt.Errorf("%w, got %s", ErrInvalidToolName, name)
+		}
+		res.name = name
+	}
+	if description := directiveArgument(d, "description"); description != "" {
+		res.description = description
+	}
+	res.hidden = directiveArgument(d, "hidden") == "true"
+	return res, nil
+}
+
+// argDescription returns the description of an argument or input field, which
+// can be overridden using the @mcpArg directive.
+func argDescription(description string, directives ast.DirectiveList) string {
+	if d := directives.ForName(ArgDirective); d != nil {
+		if override := directiveArgument(d, "description"); override != "" {
+			description = override
+		}
+	}
+	return strings.ReplaceAll(description, "\n", " ")
+}
+
+func directiveArgument(d *ast.Directive, name string) string {
+	arg := d.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil || arg.Value.Kind == ast.NullValue {
+		return ""
+	}
+	return arg.Value.Raw
+}
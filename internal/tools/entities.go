@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/federation"
+)
+
+// entityTools adds a lookup tool for every type in the federated _Entity union.
+func entityTools(schema *Schema, b *builder) {
+	entityUnion, ok := schema.astSchema.Types[federation.EntityUnion]
+	if !ok {
+		return
+	}
+	for _, name := range entityUnion.Types {
+		if !b.opts.selected(entityToolName(name), QueryResolver) {
+			continue
+		}
+		def, ok := schema.astSchema.Types[name]
+		if !ok {
+			b.add(nil, withOperation(typeError(name, ErrTypeNotFound), QueryResolver, entityToolName(name)))
+			continue
+		}
+		tool, err := entityTool(def, schema, b.opts.selectionLimits(entityToolName(name)), b.opts.Selections[entityToolName(name)])
+		if err != nil {
+			err = withOperation(err, QueryResolver, entityToolName(name))
+		}
+		b.add(tool, err)
+	}
+}
+
+func entityToolName(typeName string) string {
+	return "lookup" + typeName
+}
+
+// entityTool returns the tool looking up entities of the type, the selection set of the entity is limited
+// or overridden as for the other tools.
+func entityTool(def *ast.Definition, schema *Schema, limits SelectionLimits, override Selection) (*Tool, error) {
+	keys := federation.ResolvableKeys(def)
+	name := entityToolName(def.Name)
+	description := fmt.Sprintf("Look up %s entities by one of their keys: %s.", def.Name, strings.Join(keys, ", "))
+	if def.Description != "" {
+		description += " " + strings.ReplaceAll(def.Description, "\n", " ")
+	}
+
+	fields, err := responseSelectionSet(def.Name, schema, limits, override)
+	if err != nil {
+		return nil, withPath(err, federation.EntitiesField)
+	}
+	responseQuery := renderSelectionSet(fragmentSelectionSet(def.Name, fields), 4)
+
+	query := fmt.Sprintf(`
+		query %s ($representations: [_Any!]!) {
+			%s(representations: $representations) %s
+		}
+	`, name, federation.EntitiesField, responseQuery)
+
+	inputSchema := newInputSchemaBuilder(schema)
+	items, err := representationItems(def, keys, inputSchema)
+	if err != nil {
+		return nil, withPath(err, "$representations")
+	}
+	return &Tool{
+		Name:          name,
+		Description:   description,
+		OperationType: QueryResolver,
+		Operation:     name,
+		Args: []*ToolArg{
+			{
+				Name:     "representations",
+				Required: true,
+				Schema: &JSONSchema{
+					Type:        TypeArray.PropertyDefinitionString(),
+					Description: fmt.Sprintf("The key fields of the %s entities to look up.", def.Name),
+					Items:       items,
+				},
+			},
+		},
+		Defs:  inputSchema.defs,
+		Query: query,
+	}, nil
+}
+
+// representationItems returns the schema of an entity representation, which consists of the
+// __typename and the fields of one of the keys of the entity.
+func representationItems(def *ast.Definition, keys []string, b *inputSchemaBuilder) (*JSONSchema, error) {
+	res := &JSONSchema{
+		Type: TypeObject.PropertyDefinitionString(),
+		Properties: map[string]*JSONSchema{
+			"__typename": {Type: TypeString.PropertyDefinitionString(), Enum: []string{def.Name}},
+		},
+		Required: []string{"__typename"},
+	}
+	keyFields := ast.SelectionSet{}
+	seen := map[string]bool{}
+	for _, key := range keys {
+		selectionSet, err := parseFieldSet(key, ErrInvalidKey)
+		if err != nil {
+			return nil, typeError(def.Name, err)
+		}
+		for _, s := range selectionSet {
+			field, ok := s.(*ast.Field)
+			if !ok || seen[field.Name] {
+				continue
+			}
+			seen[field.Name] = true
+			keyFields = append(keyFields, field)
+			// with multiple keys only one of them has to be provided.
+			if len(keys) == 1 {
+				res.Required = append(res.Required, field.Name)
+			}
+		}
+	}
+	if err := keyFieldProperties(res.Properties, def, keyFields, b); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// keyFieldProperties adds the schema of every key field in the selection set to the properties.
+func keyFieldProperties(properties map[string]*JSONSchema, def *ast.Definition, selectionSet ast.SelectionSet, b *inputSchemaBuilder) error {
+	for _, s := range selectionSet {
+		selection, ok := s.(*ast.Field)
+		if !ok {
+			continue
+		}
+		field := def.Fields.ForName(selection.Name)
+		if field == nil {
+			return withPath(typeError(def.Name, ErrFieldNotFound), selection.Name)
+		}
+		if len(selection.SelectionSet) == 0 {
+			property, err := b.argSchema(field.Type, field.Description, nil, field.Directives)
+			if err != nil {
+				return withPath(err, field.Name)
+			}
+			properties[field.Name] = property
+			continue
+		}
+		subType, ok := b.schema.astSchema.Types[field.Type.Name()]
+		if !ok {
+			return withPath(typeError(field.Type.Name(), ErrTypeNotFound), field.Name)
+		}
+		property := &JSONSchema{
+			Type:        TypeObject.PropertyDefinitionString(),
+			Description: strings.ReplaceAll(field.Description, "\n", " "),
+			Properties:  map[string]*JSONSchema{},
+		}
+		if err := keyFieldProperties(property.Properties, subType, selection.SelectionSet, b); err != nil {
+			return withPath(err, field.Name)
+		}
+		properties[field.Name] = property
+	}
+	return nil
+}
+
+// parseFieldSet parses a selection set without surrounding braces, a parse error is wrapped in invalid.
+func parseFieldSet(fieldSet string, invalid error) (ast.SelectionSet, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: "{" + fieldSet + "}"})
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", invalid, fieldSet, err)
+	}
+	return doc.Operations[0].SelectionSet, nil
+}
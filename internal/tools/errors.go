@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors returned when no tool can be generated for an operation.
+var (
+	// ErrTypeNotFound is returned when a type is referenced which is not defined in the schema.
+	ErrTypeNotFound = errors.New("type not found in schema")
+	// ErrUnsupportedType is returned when a type cannot be used as tool argument.
+	ErrUnsupportedType = errors.New("unsupported argument type")
+	// ErrFieldNotFound is returned when a field is referenced which is not defined on its type.
+	ErrFieldNotFound = errors.New("field not found")
+	// ErrInvalidToolName is returned when a tool name is not a valid GraphQL name.
+	ErrInvalidToolName = errors.New("tool names must be valid GraphQL names")
+	// ErrEmptySelection is returned when none of the fields of the returned type can be selected.
+	ErrEmptySelection = errors.New("no fields can be selected within the selection limits")
+	// ErrInvalidSelection is returned when the selection set configured for a tool is invalid.
+	ErrInvalidSelection = errors.New("invalid selection set")
+	// ErrAnonymousOperation is returned for operation documents containing an operation without name.
+	ErrAnonymousOperation = errors.New("operations must be named to be used as tool")
+	// ErrInvalidKey is returned when the fields of a federation key cannot be parsed.
+	ErrInvalidKey = errors.New("invalid key field set")
+	// ErrDuplicateToolName is returned when a tool has the same name as a tool generated for another operation.
+	ErrDuplicateToolName = errors.New("duplicate tool name")
+	// ErrOperationSelection is returned when selection limits or a selection are configured for an operation document.
+	ErrOperationSelection = errors.New("selection limits and selections can't be configured for operation documents, change the document instead")
+	// ErrUnknownTool is returned when a tool is configured which matches no operation.
+	ErrUnknownTool = errors.New("configured tool matches no operation")
+)
+
+// Error describes why no tool can be generated for an operation.
+type Error struct {
+	// OperationType is the type of the operation.
+	OperationType ResolverType
+	// Operation is the name of the field on the root type, or the name of the entity tool.
+	Operation string
+	// Path is the path to the offending argument or field. Arguments start with a $, e.g. $input.filter,
+	// fields in the response start with the operation name, e.g. books.author.
+	Path []string
+	// Type is the name of the offending type.
+	Type string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(string(e.OperationType) + " " + e.Operation)
+	if len(e.Path) > 0 {
+		b.WriteString(", " + strings.Join(e.Path, "."))
+	}
+	if e.Type != "" {
+		b.WriteString(", type " + e.Type)
+	}
+	b.WriteString(": " + e.Err.Error())
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// typeError returns an error for the type, the path is added by the callers using withPath.
+func typeError(typeName string, err error) error {
+	return &Error{Type: typeName, Err: err}
+}
+
+// withPath prepends the path segment to the path of the error.
+func withPath(err error, segment string) error {
+	var toolErr *Error
+	if errors.As(err, &toolErr) {
+		toolErr.Path = append([]string{segment}, toolErr.Path...)
+	}
+	return err
+}
+
+// withFieldPath prepends the path segments to the path of the error.
+func withFieldPath(err error, path []string) error {
+	for i := len(path) - 1; i >= 0; i-- {
+		err = withPath(err, path[i])
+	}
+	return err
+}
+
+// withOperation sets the operation of the error.
+func withOperation(err error, operationType ResolverType, operation string) error {
+	var toolErr *Error
+	if !errors.As(err, &toolErr) {
+		return &Error{OperationType: operationType, Operation: operation, Err: err}
+	}
+	toolErr.OperationType = operationType
+	toolErr.Operation = operation
+	return err
+}
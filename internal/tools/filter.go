@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Pattern matches tool names. A pattern is either an exact name, a glob as
+// supported by path.Match, e.g. delete*, or a regular expression enclosed in
+// slashes, e.g. /^(create|update)User$/. A pattern can be prefixed with the
+// operation type to only match operations of that type, e.g. mutation.*.
+type Pattern struct {
+	resolverType ResolverType
+	glob         string
+	regexp       *regexp.Regexp
+}
+
+// ParsePatterns parses the patterns, an error is returned for invalid globs and regular expressions.
+func ParsePatterns(patterns ...string) ([]Pattern, error) {
+	res := make([]Pattern, 0, len(patterns))
+	for _, p := range patterns {
+		pattern, err := parsePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pattern)
+	}
+	return res, nil
+}
+
+func parsePattern(p string) (Pattern, error) {
+	pattern := Pattern{}
+	for _, t := range []ResolverType{QueryResolver, MutationResolver, SubscriptionResolver} {
+		if strings.HasPrefix(p, string(t)+".") {
+			pattern.resolverType = t
+			p = strings.TrimPrefix(p, string(t)+".")
+			break
+		}
+	}
+	if len(p) > 1 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		re, err := regexp.Compile(p[1 : len(p)-1])
+		if err != nil {
+			return Pattern{}, fmt.Errorf("invalid regular expression %s: %w", p, err)
+		}
+		pattern.regexp = re
+		return pattern, nil
+	}
+	if _, err := path.Match(p, ""); err != nil {
+		return Pattern{}, fmt.Errorf("invalid pattern %s: %w", p, err)
+	}
+	pattern.glob = p
+	return pattern, nil
+}
+
+// Match reports whether the pattern matches the tool of the given operation type.
+func (p Pattern) Match(name string, resolverType ResolverType) bool {
+	if p.resolverType != "" && p.resolverType != resolverType {
+		return false
+	}
+	if p.regexp != nil {
+		return p.regexp.MatchString(name)
+	}
+	ok, err := path.Match(p.glob, name)
+	return err == nil && ok
+}
+
+func matchAny(patterns []Pattern, name string, resolverType ResolverType) bool {
+	for _, p := range patterns {
+		if p.Match(name, resolverType) {
+			return true
+		}
+	}
+	return false
+}
+
+// selected reports whether a tool is generated for the operation.
+func (o *Options) selected(name string, resolverType ResolverType) bool {
+	if o.QueriesOnly && resolverType != QueryResolver {
+		return false
+	}
+	if o.NoMutations && resolverType == MutationResolver {
+		return false
+	}
+	if len(o.Include) > 0 && !matchAny(o.Include, name, resolverType) {
+		return false
+	}
+	return !matchAny(o.Exclude, name, resolverType)
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// JSONSchema is a JSON schema describing the input of a tool. Input objects are defined once
+// in the $defs of the input schema of the tool and referenced using $ref.
+type JSONSchema struct {
+	Ref         string                 `json:"$ref,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Const       any                    `json:"const,omitempty"`
+	OneOf       []*JSONSchema          `json:"oneOf,omitempty"`
+	Default     any                    `json:"default,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Defs        map[string]*JSONSchema `json:"$defs,omitempty"`
+	// Nullable indicates the value can also be null.
+	Nullable bool `json:"-"`
+}
+
+// MarshalJSON marshals the schema, a nullable schema accepts null in addition to its type.
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	type schema JSONSchema
+	if !s.Nullable {
+		return json.Marshal(schema(s))
+	}
+	nullable := struct {
+		schema
+		Ref   string        `json:"$ref,omitempty"`
+		Type  any           `json:"type,omitempty"`
+		Enum  []any         `json:"enum,omitempty"`
+		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+		OneOf []*JSONSchema `json:"oneOf,omitempty"`
+	}{schema: schema(s)}
+	if s.Ref != "" {
+		nullable.AnyOf = []*JSONSchema{{Ref: s.Ref}, {Type: "null"}}
+		return json.Marshal(nullable)
+	}
+	nullable.Type = []string{s.Type, "null"}
+	for _, enum := range s.Enum {
+		nullable.Enum = append(nullable.Enum, enum)
+	}
+	if len(s.Enum) > 0 {
+		nullable.Enum = append(nullable.Enum, nil)
+	}
+	if len(s.OneOf) > 0 {
+		nullable.OneOf = append(append(nullable.OneOf, s.OneOf...), &JSONSchema{Type: "null"})
+	}
+	return json.Marshal(nullable)
+}
+
+// defRef returns the reference to the definition of the type in the $defs of the input schema.
+func defRef(typeName string) string {
+	return "#/$defs/" + typeName
+}
+
+// inputSchemaBuilder builds the JSON schemas of the arguments of a tool. Every input object is
+// added once to the defs, such that recursive input objects can be represented.
+type inputSchemaBuilder struct {
+	schema *Schema
+	defs   map[string]*JSONSchema
+}
+
+func newInputSchemaBuilder(schema *Schema) *inputSchemaBuilder {
+	return &inputSchemaBuilder{
+		schema: schema,
+		defs:   map[string]*JSONSchema{},
+	}
+}
+
+// argSchema returns the schema of an argument or input field with the given description,
+// optional default value and directives.
+func (b *inputSchemaBuilder) argSchema(t *ast.Type, description string, defaultValue *ast.Value, directives ast.DirectiveList) (*JSONSchema, error) {
+	res, err := b.typeSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	// the description of the type, e.g. of a custom scalar, is appended to the description.
+	description = joinDescriptions(argDescription(description, directives), res.Description)
+	res.Description, res.Deprecated = b.schema.markDeprecated(description, directives)
+	if defaultValue != nil {
+		res.Default = jsonValue(defaultValue)
+	}
+	return res, nil
+}
+
+// isRequired reports whether a value has to be provided for an argument or input field,
+// which is the case for non-null types without a default value.
+func isRequired(t *ast.Type, defaultValue *ast.Value) bool {
+	return t.NonNull && defaultValue == nil
+}
+
+// jsonValue converts a constant GraphQL value, such as a default value, to its JSON representation.
+func jsonValue(v *ast.Value) any {
+	switch v.Kind {
+	case ast.ListValue:
+		res := []any{}
+		for _, child := range v.Children {
+			res = append(res, jsonValue(child.Value))
+		}
+		return res
+	case ast.ObjectValue:
+		res := map[string]any{}
+		for _, child := range v.Children {
+			res[child.Name] = jsonValue(child.Value)
+		}
+		return res
+	default:
+		value, err := v.Value(nil)
+		if err != nil {
+			// e.g. integers exceeding 64 bits.
+			return v.Raw
+		}
+		return value
+	}
+}
+
+// typeSchema returns the schema of the type, which is nullable unless the type is non-null.
+func (b *inputSchemaBuilder) typeSchema(t *ast.Type) (*JSONSchema, error) {
+	res, err := b.nonNullTypeSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	res.Nullable = !t.NonNull
+	return res, nil
+}
+
+// nonNullTypeSchema returns the schema of the type, regardless of whether it is nullable.
+// https://spec.graphql.org/draft/#sec-Type-System
+func (b *inputSchemaBuilder) nonNullTypeSchema(t *ast.Type) (*JSONSchema, error) { //nolint:revive
+	if isArray(t) {
+		items, err := b.typeSchema(t.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: TypeArray.PropertyDefinitionString(), Items: items}, nil
+	}
+	switch t.Name() {
+	case "String", "ID":
+		return &JSONSchema{Type: TypeString.PropertyDefinitionString()}, nil
+	case "Int":
+		return &JSONSchema{Type: TypeInteger.PropertyDefinitionString()}, nil
+	case "Float":
+		return &JSONSchema{Type: TypeNumber.PropertyDefinitionString()}, nil
+	case "Boolean":
+		return &JSONSchema{Type: TypeBoolean.PropertyDefinitionString()}, nil
+	}
+	def, ok := b.schema.astSchema.Types[t.Name()]
+	if !ok {
+		return nil, typeError(t.Name(), ErrTypeNotFound)
+	}
+	switch def.Kind {
+	case ast.Scalar:
+		scalar, _ := b.schema.customScalar(t)
+		return &JSONSchema{
+			Type:        scalar.Type.PropertyDefinitionString(),
+			Description: scalar.Description,
+			Format:      scalar.Format,
+			Pattern:     scalar.Pattern,
+		}, nil
+	case ast.Enum:
+		return b.enumSchema(def), nil
+	case ast.InputObject:
+		if err := b.define(def); err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Ref: defRef(def.Name)}, nil
+	default:
+		return nil, typeError(t.Name(), ErrUnsupportedType)
+	}
+}
+
+// enumSchema returns the schema of an enum. If any of the values is described or marked as deprecated,
+// every value is also listed in oneOf together with its description.
+func (b *inputSchemaBuilder) enumSchema(def *ast.Definition) *JSONSchema {
+	res := &JSONSchema{Type: TypeEnum.PropertyDefinitionString()}
+	values := []*JSONSchema{}
+	described := false
+	for _, enum := range def.EnumValues {
+		if b.schema.excluded(enum.Directives) {
+			continue
+		}
+		description, deprecated := b.schema.markDeprecated(strings.ReplaceAll(enum.Description, "\n", " "), enum.Directives)
+		described = described || description != ""
+		res.Enum = append(res.Enum, enum.Name)
+		values = append(values, &JSONSchema{Const: enum.Name, Description: description, Deprecated: deprecated})
+	}
+	if described {
+		res.OneOf = values
+	}
+	return res
+}
+
+// define adds the input object to the defs, unless it is already defined.
+func (b *inputSchemaBuilder) define(def *ast.Definition) error {
+	if _, ok := b.defs[def.Name]; ok {
+		return nil
+	}
+	res := &JSONSchema{
+		Type:        TypeObject.PropertyDefinitionString(),
+		Description: def.Description,
+		Properties:  map[string]*JSONSchema{},
+	}
+	// defined before resolving the fields, as the input object can reference itself.
+	b.defs[def.Name] = res
+	for _, f := range def.Fields {
+		if b.schema.excludedArg(f.Type, f.DefaultValue, f.Directives) {
+			continue
+		}
+		property, err := b.argSchema(f.Type, f.Description, f.DefaultValue, f.Directives)
+		if err != nil {
+			return withPath(err, f.Name)
+		}
+		res.Properties[f.Name] = property
+		if isRequired(f.Type, f.DefaultValue) {
+			res.Required = append(res.Required, f.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+	// loads the validation rules of the GraphQL specification.
+	_ "github.com/vektah/gqlparser/v2/validator/rules"
+)
+
+// operationTools adds a tool for every named operation in the operation documents. The documents
+// are validated against the schema, fragments may be shared between documents.
+func operationTools(schema *Schema, sources []*ast.Source, b *builder) {
+	doc := &ast.QueryDocument{}
+	for _, src := range sources {
+		srcDoc, err := parser.ParseQuery(src)
+		if err != nil {
+			b.add(nil, fmt.Errorf("invalid operation document: %w", err))
+			return
+		}
+		doc.Operations = append(doc.Operations, srcDoc.Operations...)
+		doc.Fragments = append(doc.Fragments, srcDoc.Fragments...)
+	}
+	for _, op := range doc.Operations {
+		if op.Name != "" {
+			b.known[op.Name] = true
+		}
+	}
+	if errs := validator.Validate(schema.astSchema, doc); len(errs) > 0 {
+		for _, err := range errs {
+			b.add(nil, fmt.Errorf("invalid operation document: %w", err))
+		}
+		return
+	}
+	for _, op := range doc.Operations {
+		resolverType := ResolverType(op.Operation)
+		if op.Name != "" && !b.opts.selected(op.Name, resolverType) {
+			continue
+		}
+		b.add(operationTool(op, doc, schema, b.opts))
+	}
+}
+
+// operationTool returns the tool for the operation, of which the arguments are the variables of the operation.
+// Comments preceding the operation and its variables are used as description. The selection set of the
+// operation is used as is, configuring selection limits or a selection for the tool results in an error.
+func operationTool(op *ast.OperationDefinition, doc *ast.QueryDocument, schema *Schema, opts *Options) (*Tool, error) {
+	resolverType := ResolverType(op.Operation)
+	if op.Name == "" {
+		return nil, &Error{OperationType: resolverType, Operation: fmt.Sprintf("%s:%d", op.Position.Src.Name, op.Position.Line), Err: ErrAnonymousOperation}
+	}
+	_, limits := opts.ToolLimits[op.Name]
+	_, selection := opts.Selections[op.Name]
+	if limits || selection {
+		return nil, &Error{OperationType: resolverType, Operation: op.Name, Err: ErrOperationSelection}
+	}
+	description := commentText(op.Comment)
+	if description == "" {
+		description = fmt.Sprintf("Executes the %s operation %s.", op.Operation, op.Name)
+	}
+	tool := &Tool{
+		Name:          op.Name,
+		Description:   description,
+		OperationType: resolverType,
+		Operation:     op.Name,
+	}
+	if resolverType == SubscriptionResolver {
+		tool.Subscription = opts.subscriptions()
+	}
+	inputSchema := newInputSchemaBuilder(schema)
+	for _, v := range op.VariableDefinitions {
+		argSchema, err := inputSchema.argSchema(v.Type, commentText(v.Comment), v.DefaultValue, nil)
+		if err != nil {
+			return nil, withOperation(withPath(err, "$"+v.Variable), resolverType, op.Name)
+		}
+		tool.Args = append(tool.Args, &ToolArg{
+			Name:     v.Variable,
+			Required: isRequired(v.Type, v.DefaultValue),
+			Schema:   argSchema,
+		})
+	}
+	tool.Defs = inputSchema.defs
+
+	var query strings.Builder
+	formatter.NewFormatter(&query, formatter.WithIndent("\t")).FormatQueryDocument(&ast.QueryDocument{
+		Operations: ast.OperationList{op},
+		Fragments:  usedFragments(op.SelectionSet, doc, map[string]bool{}),
+	})
+	tool.Query = query.String()
+	return tool, nil
+}
+
+// usedFragments returns the fragments which are spread in the selection set, including the
+// fragments spread within these fragments.
+func usedFragments(selectionSet ast.SelectionSet, doc *ast.QueryDocument, seen map[string]bool) ast.FragmentDefinitionList {
+	res := ast.FragmentDefinitionList{}
+	for _, s := range selectionSet {
+		switch s := s.(type) {
+		case *ast.Field:
+			res = append(res, usedFragments(s.SelectionSet, doc, seen)...)
+		case *ast.InlineFragment:
+			res = append(res, usedFragments(s.SelectionSet, doc, seen)...)
+		case *ast.FragmentSpread:
+			if seen[s.Name] {
+				continue
+			}
+			seen[s.Name] = true
+			fragment := doc.Fragments.ForName(s.Name)
+			res = append(res, fragment)
+			res = append(res, usedFragments(fragment.SelectionSet, doc, seen)...)
+		}
+	}
+	return res
+}
+
+// commentText returns the text of the comments, joined by spaces.
+func commentText(comments *ast.CommentGroup) string {
+	if comments == nil {
+		return ""
+	}
+	lines := []string{}
+	for _, c := range comments.List {
+		if text := strings.TrimSpace(c.Text()); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, " ")
+}
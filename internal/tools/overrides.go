@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Selection overrides the generated selection set of the response of a tool.
+type Selection struct {
+	// Fields is an explicit selection set, e.g. "id title author { name }", which is used instead of
+	// the generated selection set. It takes precedence over Include and Exclude and is not limited.
+	Fields string
+	// Include limits the generated selection set to the fields with the given paths, e.g. author.name.
+	// Fields of the possible types of unions and interfaces are referenced by their name.
+	Include []string
+	// Exclude removes the fields with the given paths from the generated selection set.
+	Exclude []string
+}
+
+// responseSelectionSet returns the selection set of the response of a field of the given type, which
+// is either the explicit selection set of the override, or the generated selection set limited to
+// the included fields, without the excluded fields and within the limits.
+func responseSelectionSet(typeName string, schema *Schema, limits SelectionLimits, override Selection) ([]*selection, error) {
+	if override.Fields != "" {
+		selectionSet, err := parseFieldSet(override.Fields, ErrInvalidSelection)
+		if err != nil {
+			return nil, typeError(typeName, err)
+		}
+		return explicitSelectionSet(typeName, selectionSet, schema)
+	}
+	include, exclude, err := overridePaths(typeName, override, schema)
+	if err != nil {
+		return nil, err
+	}
+	return generateSelectionSet(typeName, schema, limits, include, exclude)
+}
+
+// overridePaths returns the paths of the included and excluded fields of the override, validated
+// against the given type. The included paths are nil if all fields are included.
+func overridePaths(typeName string, override Selection, schema *Schema) ([][]string, [][]string, error) {
+	include, err := fieldPaths(typeName, override.Include, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	exclude, err := fieldPaths(typeName, override.Exclude, schema)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(include) == 0 {
+		include = nil
+	}
+	return include, exclude, nil
+}
+
+// explicitSelectionSet converts the selection set of a field of the given type, validating it against the schema.
+// Only fields and inline fragments without aliases, arguments and directives are supported.
+func explicitSelectionSet(typeName string, selectionSet ast.SelectionSet, schema *Schema) ([]*selection, error) {
+	def, ok := schema.astSchema.Types[typeName]
+	if !ok {
+		return nil, typeError(typeName, ErrTypeNotFound)
+	}
+	if isLeafType(typeName, schema) {
+		return nil, typeError(typeName, fmt.Errorf("%w: scalars and enums have no selection set", ErrInvalidSelection))
+	}
+	res := []*selection{}
+	for _, s := range selectionSet {
+		switch s := s.(type) {
+		case *ast.Field:
+			if s.Alias != s.Name || len(s.Arguments) > 0 || len(s.Directives) > 0 {
+				return nil, withPath(typeError(def.Name, fmt.Errorf("%w: aliases, arguments and directives are not supported", ErrInvalidSelection)), s.Name)
+			}
+			if s.Name == "__typename" {
+				res = append(res, &selection{name: s.Name, leaf: true})
+				continue
+			}
+			field := def.Fields.ForName(s.Name)
+			if field == nil {
+				return nil, withPath(typeError(def.Name, ErrFieldNotFound), s.Name)
+			}
+			leaf := isLeafType(field.Type.Name(), schema)
+			if !leaf && len(s.SelectionSet) == 0 {
+				return nil, withPath(typeError(field.Type.Name(), fmt.Errorf("%w: objects, interfaces and unions require a selection set", ErrInvalidSelection)), s.Name)
+			}
+			fields := []*selection{}
+			if !leaf || len(s.SelectionSet) > 0 {
+				var err error
+				fields, err = explicitSelectionSet(field.Type.Name(), s.SelectionSet, schema)
+				if err != nil {
+					return nil, withPath(err, s.Name)
+				}
+			}
+			res = append(res, &selection{name: s.Name, leaf: leaf, selectionSet: fields})
+		case *ast.InlineFragment:
+			if !isPossibleType(def, s.TypeCondition, schema) {
+				return nil, typeError(s.TypeCondition, fmt.Errorf("%w: not a possible type of %s", ErrInvalidSelection, def.Name))
+			}
+			fields, err := explicitSelectionSet(s.TypeCondition, s.SelectionSet, schema)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, &selection{typeCondition: s.TypeCondition, selectionSet: fields})
+		default:
+			return nil, typeError(def.Name, fmt.Errorf("%w: fragment spreads are not supported", ErrInvalidSelection))
+		}
+	}
+	return res, nil
+}
+
+// isPossibleType reports whether an inline fragment on the type condition can be used within the selection set of the type.
+func isPossibleType(def *ast.Definition, typeCondition string, schema *Schema) bool {
+	if typeCondition == def.Name {
+		return true
+	}
+	for _, possibleType := range schema.astSchema.GetPossibleTypes(def) {
+		if possibleType.Name == typeCondition {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldPaths splits the dot separated field paths, validating them against the schema.
+func fieldPaths(typeName string, paths []string, schema *Schema) ([][]string, error) {
+	res := [][]string{}
+	for _, path := range paths {
+		fields := strings.Split(path, ".")
+		if err := validateFieldPath(typeName, fields, schema); err != nil {
+			return nil, err
+		}
+		res = append(res, fields)
+	}
+	return res, nil
+}
+
+// validateFieldPath checks that the fields of the path exist, starting at the given type.
+func validateFieldPath(typeName string, path []string, schema *Schema) error {
+	if len(path) == 0 {
+		return nil
+	}
+	def, ok := schema.astSchema.Types[typeName]
+	if !ok {
+		return typeError(typeName, ErrTypeNotFound)
+	}
+	field := def.Fields.ForName(path[0])
+	if def.Kind == ast.Union || def.Kind == ast.Interface {
+		for _, possibleType := range schema.astSchema.GetPossibleTypes(def) {
+			if field != nil {
+				break
+			}
+			field = possibleType.Fields.ForName(path[0])
+		}
+	}
+	if field == nil {
+		return withPath(typeError(def.Name, ErrFieldNotFound), path[0])
+	}
+	return withPath(validateFieldPath(field.Type.Name(), path[1:], schema), path[0])
+}
+
+// matchPaths reports whether one of the paths is the field itself, and returns the remainder
+// of the paths which continue within the field.
+func matchPaths(name string, paths [][]string) (bool, [][]string) {
+	whole := false
+	rest := [][]string{}
+	for _, path := range paths {
+		if path[0] != name {
+			continue
+		}
+		if len(path) == 1 {
+			whole = true
+			continue
+		}
+		rest = append(rest, path[1:])
+	}
+	return whole, rest
+}
@@ -0,0 +1,187 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// nodeField is the field of the Relay Node interface resolving any object by its global ID.
+const nodeField = "node"
+
+// Resource describes an MCP resource template resolving an object by its ID, using a query
+// returning a single object, e.g. book(id: ID!): Book, or using the node query of the Node interface.
+type Resource struct {
+	// TypeName is the name of the object type.
+	TypeName    string
+	Description string
+	// Variable is the variable of the query containing the ID, which is also the variable of the URI template.
+	Variable string
+	// OperationName is the name of the query, e.g. bookResource.
+	OperationName string
+	// Query resolves the object.
+	Query string
+}
+
+// GetResourcesForSchema returns a resource for every object type which can be resolved by a single
+// ID or String argument. The first query returning the type is used, objects without such a query
+// are resolved using the node query if present. Queries for which no tool is generated, as they are
+// hidden or not selected, are skipped. The selection set is limited and overridden as for the tool
+// of the query. An *Error is returned for every type for which no resource can be generated, unless
+// WithSkipInvalid is used.
+func GetResourcesForSchema(astSchema *ast.Schema, options ...Option) ([]Resource, error) {
+	opts := newOptions(options)
+	schema := newSchema(astSchema, opts)
+	if astSchema.Query == nil {
+		return nil, nil
+	}
+	res := []Resource{}
+	errs := []error{}
+	add := func(resource *Resource, err error) {
+		switch {
+		case err != nil && opts.Warn != nil:
+			opts.Warn(err)
+		case err != nil:
+			errs = append(errs, err)
+		case resource != nil:
+			res = append(res, *resource)
+		}
+	}
+	resolved := map[string]bool{}
+	for _, v := range astSchema.Query.Fields {
+		if v.Name == "__schema" || v.Name == "__type" {
+			continue
+		}
+		def, ok := astSchema.Types[v.Type.Name()]
+		variable := idArgument(v)
+		if !ok || def.Kind != ast.Object || isArray(v.Type) || variable == "" || resolved[def.Name] ||
+			schema.excluded(v.Directives) {
+			continue
+		}
+		directive, err := toolDirectiveForField(v)
+		if err != nil {
+			add(nil, withOperation(err, QueryResolver, v.Name))
+			continue
+		}
+		if !selectedQuery(directive, opts) {
+			continue
+		}
+		resolved[def.Name] = true
+		add(fieldResource(v, def, variable, directive, schema, opts))
+	}
+	node := astSchema.Query.Fields.ForName(nodeField)
+	if node == nil || idArgument(node) == "" || schema.excluded(node.Directives) {
+		return res, errors.Join(errs...)
+	}
+	directive, err := toolDirectiveForField(node)
+	if err != nil || !selectedQuery(directive, opts) {
+		return res, errors.Join(errs...)
+	}
+	nodeInterface, ok := astSchema.Types[node.Type.Name()]
+	if !ok || nodeInterface.Kind != ast.Interface {
+		return res, errors.Join(errs...)
+	}
+	for _, def := range astSchema.GetPossibleTypes(nodeInterface) {
+		if def.Kind != ast.Object || resolved[def.Name] {
+			continue
+		}
+		add(nodeResource(node, def, directive, schema, opts))
+	}
+	return res, errors.Join(errs...)
+}
+
+// selectedQuery reports whether the query is selected for a tool, the queries which are hidden
+// or filtered out are not exposed as resource either.
+func selectedQuery(directive toolDirective, opts *Options) bool {
+	return !directive.hidden && opts.selected(directive.name, QueryResolver)
+}
+
+// idArgument returns the name of the only required argument of the field if it is an ID or String,
+// otherwise it's empty.
+func idArgument(v *ast.FieldDefinition) string {
+	res := ""
+	for _, a := range v.Arguments {
+		if !isRequired(a.Type, a.DefaultValue) {
+			continue
+		}
+		if res != "" || isArray(a.Type) || (a.Type.Name() != "ID" && a.Type.Name() != "String") {
+			return ""
+		}
+		res = a.Name
+	}
+	return res
+}
+
+func resourceDescription(def *ast.Definition, variable string) string {
+	description := fmt.Sprintf("%s by its %s.", def.Name, variable)
+	if def.Description != "" {
+		description += " " + strings.ReplaceAll(def.Description, "\n", " ")
+	}
+	return description
+}
+
+// fieldResource returns the resource resolving the object using the query field.
+func fieldResource(v *ast.FieldDefinition, def *ast.Definition, variable string, directive toolDirective, schema *Schema, opts *Options) (*Resource, error) {
+	operationName := resourceOperationName(def)
+	query, err := getQuery(v, operationName, schema, QueryResolver,
+		opts.selectionLimits(directive.name), opts.Selections[directive.name])
+	if err != nil {
+		return nil, withOperation(withPath(err, v.Name), QueryResolver, v.Name)
+	}
+	return &Resource{
+		TypeName:      def.Name,
+		Description:   resourceDescription(def, variable),
+		Variable:      variable,
+		OperationName: operationName,
+		Query:         query,
+	}, nil
+}
+
+// nodeResource returns the resource resolving the object using the node query.
+func nodeResource(node *ast.FieldDefinition, def *ast.Definition, directive toolDirective, schema *Schema, opts *Options) (*Resource, error) {
+	variable := idArgument(node)
+	operationName := resourceOperationName(def)
+	selectionSet, err := nodeSelectionSet(node.Type.Name(), def, schema,
+		opts.selectionLimits(directive.name), opts.Selections[directive.name])
+	if err != nil {
+		return nil, withOperation(withPath(err, nodeField), QueryResolver, operationName)
+	}
+	query := fmt.Sprintf(`
+		query %s ($%s: %s) {
+			%s(%s: $%s) %s
+		}
+	`, operationName, variable, node.Arguments.ForName(variable).Type.String(),
+		nodeField, variable, variable, renderSelectionSet(selectionSet, 4))
+	return &Resource{
+		TypeName:      def.Name,
+		Description:   resourceDescription(def, variable),
+		Variable:      variable,
+		OperationName: operationName,
+		Query:         query,
+	}, nil
+}
+
+// nodeSelectionSet returns the selection set of the node query resolving the object, which only
+// selects the fields of the object. An explicit selection set configured for the node query is used
+// as is, the included and excluded fields are validated against the interface as for the node tool.
+func nodeSelectionSet(interfaceName string, def *ast.Definition, schema *Schema, limits SelectionLimits, override Selection) ([]*selection, error) {
+	if override.Fields != "" {
+		return responseSelectionSet(interfaceName, schema, limits, override)
+	}
+	include, exclude, err := overridePaths(interfaceName, override, schema)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := generateSelectionSet(def.Name, schema, limits, include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return fragmentSelectionSet(def.Name, fields), nil
+}
+
+// resourceOperationName returns the name of the query of the resource of the type, e.g. bookResource.
+func resourceOperationName(def *ast.Definition) string {
+	return strings.ToLower(def.Name[:1]) + def.Name[1:] + "Resource"
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Scalar describes how a custom GraphQL scalar is represented in the JSON schema of a tool.
+type Scalar struct {
+	// Type is the JSON schema type of the scalar, defaults to string.
+	Type Type
+	// Format is the JSON schema format, e.g. date-time or uuid.
+	Format string
+	// Pattern is a regular expression the value has to match.
+	Pattern string
+	// Description is added to the description of arguments of the scalar type.
+	Description string
+}
+
+// DefaultScalars returns the JSON schema representation of commonly used custom scalars.
+// Scalars which are not configured are represented as string.
+func DefaultScalars() map[string]Scalar {
+	return map[string]Scalar{
+		"UUID":         {Type: TypeString, Format: "uuid"},
+		"DateTime":     {Type: TypeString, Format: "date-time"},
+		"Time":         {Type: TypeString, Format: "date-time"},
+		"Timestamp":    {Type: TypeString, Format: "date-time"},
+		"Date":         {Type: TypeString, Format: "date"},
+		"LocalTime":    {Type: TypeString, Format: "time"},
+		"Duration":     {Type: TypeString, Format: "duration"},
+		"Email":        {Type: TypeString, Format: "email"},
+		"EmailAddress": {Type: TypeString, Format: "email"},
+		"URL":          {Type: TypeString, Format: "uri"},
+		"URI":          {Type: TypeString, Format: "uri"},
+		"Decimal":      {Type: TypeString, Pattern: `^-?\d+(\.\d+)?$`, Description: "Decimal number formatted as string."},
+		"BigDecimal":   {Type: TypeString, Pattern: `^-?\d+(\.\d+)?$`, Description: "Decimal number formatted as string."},
+		"Int64":        {Type: TypeInteger},
+		"Long":         {Type: TypeInteger},
+		"BigInt":       {Type: TypeInteger},
+		"JSON":         {Type: TypeObject},
+		"JSONObject":   {Type: TypeObject},
+		"Map":          {Type: TypeObject},
+		"Upload":       {Type: TypeString, Format: "binary", Description: "File uploads are not supported."},
+	}
+}
+
+// ParseType parses a JSON schema type name into a Type.
+func ParseType(name string) (Type, error) {
+	switch strings.ToLower(name) {
+	case "", "string":
+		return TypeString, nil
+	case "number":
+		return TypeNumber, nil
+	case "integer":
+		return TypeInteger, nil
+	case "boolean":
+		return TypeBoolean, nil
+	case "object":
+		return TypeObject, nil
+	case "array":
+		return TypeArray, nil
+	default:
+		return "", fmt.Errorf("unsupported type %s, use string, number, integer, boolean, object or array", name)
+	}
+}
+
+// customScalar returns the representation of the type if it is a custom scalar.
+func (s *Schema) customScalar(t *ast.Type) (Scalar, bool) {
+	if isArray(t) {
+		return Scalar{}, false
+	}
+	def, ok := s.astSchema.Types[t.Name()]
+	if !ok || def.Kind != ast.Scalar || isBuiltInScalar(t.Name()) {
+		return Scalar{}, false
+	}
+	scalar, ok := s.scalars[t.Name()]
+	if !ok || scalar.Type == "" {
+		scalar.Type = TypeString
+	}
+	return scalar, true
+}
+
+func isBuiltInScalar(name string) bool {
+	switch name {
+	case "String", "ID", "Int", "Float", "Boolean":
+		return true
+	default:
+		return false
+	}
+}
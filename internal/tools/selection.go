@@ -0,0 +1,383 @@
+package tools
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// SelectionLimits limits the selection set of the response of a tool, such that responses of deep
+// graphs fit in the context window. Zero values mean unlimited.
+type SelectionLimits struct {
+	// MaxDepth is the maximum depth of the selected fields, the fields of the returned type have depth 1.
+	MaxDepth int
+	// MaxFields is the maximum number of selected fields.
+	MaxFields int
+}
+
+// override returns the limits, with the non-zero limits of o taking precedence.
+func (l SelectionLimits) override(o SelectionLimits) SelectionLimits {
+	if o.MaxDepth != 0 {
+		l.MaxDepth = o.MaxDepth
+	}
+	if o.MaxFields != 0 {
+		l.MaxFields = o.MaxFields
+	}
+	return l
+}
+
+// selection is a field or an inline fragment in the selection set of the response of a tool.
+type selection struct {
+	// name is the name of the field, it's empty for inline fragments.
+	name string
+	// alias is the alias of a field of an inline fragment, which is set if a field of another
+	// inline fragment has the same name but a different type.
+	alias string
+	// typeCondition is the type of an inline fragment.
+	typeCondition string
+	// leaf is set for fields of a scalar or enum type, which have no selection set.
+	leaf         bool
+	selectionSet []*selection
+}
+
+// selectionGenerator generates the selection set of a response breadth-first, the generation stops
+// at the limits instead of expanding the whole graph reachable from the returned type.
+type selectionGenerator struct {
+	schema *Schema
+	limits SelectionLimits
+	// count is the number of selected fields.
+	count int
+	// reserved is the number of fields reserved for the selection sets of the queued fields, every
+	// field selecting an object reserves one field such that it doesn't end up empty.
+	reserved int
+	queue    []*pendingSelection
+}
+
+// pendingSelection is a selected field of which the selection set is not generated yet.
+type pendingSelection struct {
+	field    *selection
+	typeName string
+	// parent is the pending selection the field is selected in, it's nil for the returned type of the tool.
+	parent *pendingSelection
+	// children is the number of fields selected in the selection set of the field which aren't removed.
+	children int
+	// depth is the depth of the fields in the selection set of the field.
+	depth int
+	// path counts the occurrences of the types on the path from the returned type of the tool to the field.
+	path map[string]int
+	// fieldPath contains the names of the fields from the returned type of the tool to the field.
+	fieldPath []string
+	// include contains the paths of the fields to select within the field, nil selects all fields.
+	include [][]string
+	// exclude contains the paths of the fields not to select within the field.
+	exclude [][]string
+}
+
+// candidate is a field which can be selected in the selection set of a pending selection.
+type candidate struct {
+	// owner is the field or inline fragment the field is selected in.
+	owner *selection
+	next  *pendingSelection
+}
+
+// generateSelectionSet returns the selection set of a field of the given type, limited to the included
+// fields, without the excluded fields and within the limits. Fields are selected breadth-first, within a
+// selection set the leaf fields are selected before the fields selecting an object, otherwise the order
+// of the schema is kept. The __typename fields and inline fragments don't count towards the limits.
+func generateSelectionSet(typeName string, schema *Schema, limits SelectionLimits, include, exclude [][]string) ([]*selection, error) {
+	root := &selection{}
+	g := &selectionGenerator{
+		schema: schema,
+		limits: limits,
+		queue: []*pendingSelection{{
+			field:    root,
+			typeName: typeName,
+			depth:    1,
+			path:     map[string]int{},
+			include:  include,
+			exclude:  exclude,
+		}},
+	}
+	for len(g.queue) > 0 {
+		p := g.queue[0]
+		g.queue = g.queue[1:]
+		if err := g.generate(p); err != nil {
+			return nil, err
+		}
+	}
+	return removeEmpty(root.selectionSet), nil
+}
+
+// generate selects the fields of the pending selection within the remaining limits, and queues the
+// selected fields selecting an object. The field is released if none of its fields are selected.
+func (g *selectionGenerator) generate(p *pendingSelection) error {
+	if p.parent != nil {
+		// the field reserved for the selection set of the pending selection is available again.
+		g.reserved--
+	}
+	candidates, err := g.candidates(p)
+	if err != nil {
+		return err
+	}
+	kept := map[*selection]bool{}
+	for _, leaves := range []bool{true, false} {
+		for _, c := range candidates {
+			if c.next.field.leaf != leaves {
+				continue
+			}
+			if !g.fits(leaves) {
+				break
+			}
+			kept[c.next.field] = true
+			g.count++
+			if !leaves {
+				g.reserved++
+			}
+		}
+	}
+	for _, c := range candidates {
+		if !kept[c.next.field] {
+			continue
+		}
+		// inline fragments are added once they select a field, after the fields outside of the fragments.
+		if c.owner != p.field && len(c.owner.selectionSet) == 0 {
+			p.field.selectionSet = append(p.field.selectionSet, c.owner)
+		}
+		c.owner.selectionSet = append(c.owner.selectionSet, c.next.field)
+		if !c.next.field.leaf {
+			g.queue = append(g.queue, c.next)
+		}
+	}
+	p.children = len(kept)
+	if p.children == 0 {
+		g.release(p)
+	}
+	return nil
+}
+
+// fits reports whether another field can be selected within the maximum number of fields, a field
+// selecting an object requires room for the first field of its selection set as well.
+func (g *selectionGenerator) fits(leaf bool) bool {
+	if g.limits.MaxFields == 0 {
+		return true
+	}
+	required := 2
+	if leaf {
+		required = 1
+	}
+	return g.count+g.reserved+required <= g.limits.MaxFields
+}
+
+// release gives back the field of a pending selection of which the selection set ends up empty, such
+// that it's removed. The parent is released as well if none of its fields remain.
+func (g *selectionGenerator) release(p *pendingSelection) {
+	if p.parent == nil {
+		return
+	}
+	g.count--
+	p.parent.children--
+	if p.parent.children == 0 {
+		g.release(p.parent)
+	}
+}
+
+// candidates returns the fields which can be selected within the pending selection, in the order of the
+// schema. For unions and interfaces __typename is selected, together with the fields of every possible
+// type using inline fragments.
+func (g *selectionGenerator) candidates(p *pendingSelection) ([]candidate, error) {
+	def, ok := g.schema.astSchema.Types[p.typeName]
+	if !ok {
+		return nil, withFieldPath(typeError(p.typeName, ErrTypeNotFound), p.fieldPath)
+	}
+	path := withType(p.path, def.Name)
+	switch def.Kind {
+	case ast.Union:
+		p.field.selectionSet = []*selection{{name: "__typename", leaf: true}}
+		return g.fragmentCandidates(p, def.Types, nil, path)
+	case ast.Interface:
+		p.field.selectionSet = []*selection{{name: "__typename", leaf: true}}
+		res := g.fieldCandidates(p, p.field, def.Fields, path)
+		possibleTypes := []string{}
+		for _, possibleType := range g.schema.astSchema.GetPossibleTypes(def) {
+			if possibleType.Kind == ast.Object {
+				possibleTypes = append(possibleTypes, possibleType.Name)
+			}
+		}
+		// the fields of the interface are already selected outside of the fragments.
+		fragments, err := g.fragmentCandidates(p, possibleTypes, def.Fields, path)
+		if err != nil {
+			return nil, err
+		}
+		return append(res, fragments...), nil
+	default:
+		return g.fieldCandidates(p, p.field, def.Fields, path), nil
+	}
+}
+
+// fragmentCandidates returns the fields of an inline fragment for each of the possible types of a union
+// or interface, except for the fields which are already selected. Fields with the same name but a
+// different type in multiple fragments can't be selected together, these are aliased by their type,
+// e.g. Author_name: name.
+func (g *selectionGenerator) fragmentCandidates(p *pendingSelection, possibleTypes []string, selected ast.FieldList, path map[string]int) ([]candidate, error) {
+	fragmentFields := []ast.FieldList{}
+	types := map[string]string{}
+	conflicts := map[string]bool{}
+	for _, possibleType := range possibleTypes {
+		def, ok := g.schema.astSchema.Types[possibleType]
+		if !ok {
+			return nil, withFieldPath(typeError(possibleType, ErrTypeNotFound), p.fieldPath)
+		}
+		fields := ast.FieldList{}
+		for _, f := range def.Fields {
+			if selected.ForName(f.Name) != nil {
+				continue
+			}
+			fields = append(fields, f)
+			if t, ok := types[f.Name]; ok && t != f.Type.String() {
+				conflicts[f.Name] = true
+			}
+			types[f.Name] = f.Type.String()
+		}
+		fragmentFields = append(fragmentFields, fields)
+	}
+	res := []candidate{}
+	for i, possibleType := range possibleTypes {
+		fragment := &selection{typeCondition: possibleType}
+		for _, c := range g.fieldCandidates(p, fragment, fragmentFields[i], withType(path, possibleType)) {
+			if conflicts[c.next.field.name] {
+				c.next.field.alias = possibleType + "_" + c.next.field.name
+			}
+			res = append(res, c)
+		}
+	}
+	return res, nil
+}
+
+// fieldCandidates returns the fields which can be selected in the owner, skipping the fields of exhausted
+// cyclic types, the excluded deprecated fields, the fields with required arguments, the fields selecting
+// an object of which the selection set exceeds the maximum depth and the fields which are not included
+// or excluded.
+func (g *selectionGenerator) fieldCandidates(p *pendingSelection, owner *selection, fields ast.FieldList, path map[string]int) []candidate {
+	res := []candidate{}
+	for _, f := range fields {
+		if g.schema.cycleExhausted(f.Type.Name(), path) || g.schema.excluded(f.Directives) || hasRequiredArguments(f) {
+			continue
+		}
+		leaf := isLeafType(f.Type.Name(), g.schema)
+		if !leaf && g.limits.MaxDepth > 0 && p.depth+1 > g.limits.MaxDepth {
+			continue
+		}
+		var include [][]string
+		if p.include != nil {
+			whole, rest := matchPaths(f.Name, p.include)
+			if !whole && len(rest) == 0 {
+				continue
+			}
+			if !whole {
+				include = rest
+			}
+		}
+		whole, exclude := matchPaths(f.Name, p.exclude)
+		if whole {
+			continue
+		}
+		res = append(res, candidate{
+			owner: owner,
+			next: &pendingSelection{
+				field:     &selection{name: f.Name, leaf: leaf},
+				typeName:  f.Type.Name(),
+				parent:    p,
+				depth:     p.depth + 1,
+				path:      path,
+				fieldPath: append(slices.Clone(p.fieldPath), f.Name),
+				include:   include,
+				exclude:   exclude,
+			},
+		})
+	}
+	return res
+}
+
+// hasRequiredArguments reports whether the field has arguments which have to be provided, such fields
+// can't be selected as the generated selection sets don't pass arguments.
+func hasRequiredArguments(f *ast.FieldDefinition) bool {
+	return slices.ContainsFunc(f.Arguments, func(a *ast.ArgumentDefinition) bool {
+		return isRequired(a.Type, a.DefaultValue)
+	})
+}
+
+// withType returns a copy of the path with an additional occurrence of the type.
+func withType(path map[string]int, typeName string) map[string]int {
+	res := maps.Clone(path)
+	res[typeName]++
+	return res
+}
+
+// fragmentSelectionSet returns the selection set of a field returning a union or interface, which only
+// selects the fields of the given possible type.
+func fragmentSelectionSet(typeName string, fields []*selection) []*selection {
+	res := []*selection{{name: "__typename", leaf: true}}
+	if len(fields) > 0 {
+		res = append(res, &selection{typeCondition: typeName, selectionSet: fields})
+	}
+	return res
+}
+
+// cycleExhausted reports whether a field of the cyclic type is no longer selected, as the type
+// already occurs on the path more often than the configured cycle depth allows.
+func (s *Schema) cycleExhausted(typeName string, path map[string]int) bool {
+	return s.cyclicTypes[typeName] && path[typeName] > s.cycleDepth
+}
+
+// isLeafType reports whether the type is a scalar or enum, which is selected without selection set.
+func isLeafType(typeName string, schema *Schema) bool {
+	def, ok := schema.astSchema.Types[typeName]
+	return ok && (def.Kind == ast.Scalar || def.Kind == ast.Enum)
+}
+
+// removeEmpty removes the fields selecting an object and the inline fragments of which the selection
+// set ends up without fields, a selection set only selecting __typename is empty as well.
+func removeEmpty(selectionSet []*selection) []*selection {
+	res := []*selection{}
+	for _, s := range selectionSet {
+		if !s.leaf {
+			s.selectionSet = removeEmpty(s.selectionSet)
+			if len(s.selectionSet) == 0 {
+				continue
+			}
+		}
+		res = append(res, s)
+	}
+	if !slices.ContainsFunc(res, func(s *selection) bool { return s.name != "__typename" }) {
+		return nil
+	}
+	return res
+}
+
+// renderSelectionSet renders the selection set, nested selection sets are indented one level deeper.
+func renderSelectionSet(selectionSet []*selection, indent int) string {
+	if len(selectionSet) == 0 {
+		return ""
+	}
+	res := "{\n"
+	for _, s := range selectionSet {
+		res += strings.Repeat("\t", indent)
+		if s.typeCondition != "" {
+			res += "... on " + s.typeCondition + " "
+		} else {
+			if s.alias != "" {
+				res += s.alias + ": "
+			}
+			res += s.name + " "
+		}
+		if len(s.selectionSet) > 0 {
+			res += renderSelectionSet(s.selectionSet, indent+1)
+		} else {
+			res += "\n"
+		}
+	}
+	res += strings.Repeat("\t", indent-1) + "}\n"
+	return res
+}
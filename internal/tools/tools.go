@@ -2,10 +2,15 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/federation"
 )
 
 // ResolverType represents the type of resolver.
@@ -25,8 +30,33 @@ const (
 type Tool struct {
 	Name        string
 	Description string
-	Args        []*ToolArg
-	Query       string
+	// OperationType is the type of the operation executed by the tool.
+	OperationType ResolverType
+	// Operation is the name of the field on the root type, the entity tool or the operation document
+	// executed by the tool.
+	Operation string
+	// Subscription bounds the events collected by tools executing a subscription.
+	Subscription Subscriptions
+	Args         []*ToolArg
+	// Defs contains the JSON schemas of the input objects referenced by the arguments.
+	Defs  map[string]*JSONSchema
+	Query string
+}
+
+// InputSchema returns the JSON schema of the arguments of the tool.
+func (t Tool) InputSchema() *JSONSchema {
+	res := &JSONSchema{
+		Type:       TypeObject.PropertyDefinitionString(),
+		Properties: map[string]*JSONSchema{},
+		Defs:       t.Defs,
+	}
+	for _, arg := range t.Args {
+		res.Properties[arg.Name] = arg.Schema
+		if arg.Required {
+			res.Required = append(res.Required, arg.Name)
+		}
+	}
+	return res
 }
 
 // Type represents the type of tool.
@@ -37,6 +67,8 @@ const (
 	TypeString Type = "String"
 	// TypeNumber is a number type.
 	TypeNumber Type = "Number"
+	// TypeInteger is an integer type.
+	TypeInteger Type = "Integer"
 	// TypeBoolean is a boolean type.
 	TypeBoolean Type = "Boolean"
 	// TypeArray is an array type.
@@ -54,6 +86,8 @@ func (t Type) String() string {
 		return "String"
 	case TypeNumber:
 		return "Number"
+	case TypeInteger:
+		return "Integer"
 	case TypeBoolean:
 		return "Boolean"
 	case TypeArray:
@@ -72,6 +106,8 @@ func (t Type) PropertyDefinitionString() string {
 		return "string"
 	case TypeNumber:
 		return "number"
+	case TypeInteger:
+		return "integer"
 	case TypeBoolean:
 		return "boolean"
 	case TypeArray:
@@ -85,309 +121,458 @@ func (t Type) PropertyDefinitionString() string {
 
 // ToolArg represents an argument for a tool.
 type ToolArg struct {
-	Name        string
-	Description string
-	Type        Type
-	Required    bool
-	Enum        []string
-	Properties  string
-	Items       string
+	Name     string
+	Required bool
+	Schema   *JSONSchema
 }
 
-// Schema represents the schema to be used for generating tools.
-type Schema struct {
-	astSchema   *ast.Schema
-	cyclicTypes map[string]bool
+// Options contains options for converting a schema to tools.
+type Options struct {
+	// EntityTools generates a tool per federated entity which resolves the entity by its keys.
+	EntityTools bool
+	// Include limits the tools to the operations matching one of the patterns.
+	Include []Pattern
+	// Exclude skips the operations matching one of the patterns.
+	Exclude []Pattern
+	// QueriesOnly only generates tools for queries.
+	QueriesOnly bool
+	// NoMutations skips the mutations.
+	NoMutations bool
+	// Scalars configures the representation of custom scalars, in addition to the DefaultScalars.
+	Scalars map[string]Scalar
+	// Deprecated configures the handling of deprecated items, defaults to DeprecatedInclude.
+	Deprecated Deprecated
+	// Limits limits the selection set of the responses of all tools.
+	Limits SelectionLimits
+	// ToolLimits overrides the Limits for the tool with the given name.
+	ToolLimits map[string]SelectionLimits
+	// Selections overrides the selection set of the response of the tool with the given name.
+	Selections map[string]Selection
+	// CycleDepth is the number of times a type which references itself, directly or through
+	// other types, is expanded again in the selection set of a response.
+	CycleDepth int
+	// SubscriptionTools generates a tool for every subscription, collecting events as configured by Subscriptions.
+	SubscriptionTools bool
+	// Subscriptions bounds the events collected by the tools executing a subscription.
+	Subscriptions Subscriptions
+	// Operations are operation documents, if set a tool is generated for every named operation in
+	// the documents instead of for every query and mutation of the schema.
+	Operations []*ast.Source
+	// Warn is called for every operation for which no tool can be generated. If set,
+	// these operations are skipped instead of failing the generation of all tools.
+	Warn func(err error)
 }
 
-func GetToolsForSchema(astSchema *ast.Schema) []Tool { //nolint:revive
-	res := []Tool{}
-	cyclicTypes := map[string]bool{}
-	for k, v := range astSchema.Types {
-		if strings.HasPrefix(k, "__") || k == "Query" || k == "Mutation" {
-			continue
-		}
-		for _, f := range v.Fields {
-			hasCycle := hasCycle(f, map[string]struct{}{}, astSchema)
-			if hasCycle {
-				cyclicTypes[k] = true
-				continue
-			}
-		}
-	}
-	schema := &Schema{
-		astSchema:   astSchema,
-		cyclicTypes: cyclicTypes,
+// Option is a function that modifies the Options.
+type Option func(*Options)
+
+// WithEntityTools generates a lookup tool for every federated entity using the _entities field.
+func WithEntityTools() Option {
+	return func(opts *Options) {
+		opts.EntityTools = true
 	}
-	if schema.astSchema.Query != nil {
-		for _, v := range schema.astSchema.Query.Fields {
-			if v.Name == "__schema" ||
-				v.Name == "__type" {
-				continue
-			}
-			res = append(res, toolFromFieldDefinition(v, schema, QueryResolver))
-		}
+}
+
+// WithInclude only generates tools for the operations matching one of the patterns.
+func WithInclude(patterns ...Pattern) Option {
+	return func(opts *Options) {
+		opts.Include = append(opts.Include, patterns...)
 	}
-	if schema.astSchema.Mutation != nil {
-		for _, v := range schema.astSchema.Mutation.Fields {
-			if v.Name == "__schema" ||
-				v.Name == "__type" {
-				continue
-			}
-			res = append(res, toolFromFieldDefinition(v, schema, MutationResolver))
-		}
+}
+
+// WithExclude skips the operations matching one of the patterns.
+func WithExclude(patterns ...Pattern) Option {
+	return func(opts *Options) {
+		opts.Exclude = append(opts.Exclude, patterns...)
 	}
-	return res
 }
 
-func hasCycle(v *ast.FieldDefinition, cycleTracker map[string]struct{}, astSchema *ast.Schema) bool {
-	if _, ok := cycleTracker[v.Name]; ok {
-		return true
+// WithQueriesOnly only generates tools for queries.
+func WithQueriesOnly() Option {
+	return func(opts *Options) {
+		opts.QueriesOnly = true
 	}
-	cycleTracker[v.Name] = struct{}{}
+}
 
-	subType, ok := astSchema.Types[v.Type.Name()]
-	if !ok {
-		return false
+// WithoutMutations skips the mutations.
+func WithoutMutations() Option {
+	return func(opts *Options) {
+		opts.NoMutations = true
 	}
-	for _, f := range subType.Fields {
-		if hasCycle(f, cycleTracker, astSchema) {
-			return true
+}
+
+// WithScalars configures the representation of custom scalars, overriding the DefaultScalars.
+func WithScalars(scalars map[string]Scalar) Option {
+	return func(opts *Options) {
+		if opts.Scalars == nil {
+			opts.Scalars = map[string]Scalar{}
+		}
+		for name, scalar := range scalars {
+			opts.Scalars[name] = scalar
 		}
 	}
-	return false
 }
 
-func toolFromFieldDefinition(v *ast.FieldDefinition, schema *Schema, resolverType ResolverType) Tool {
-	tool := Tool{
-		Name:        v.Name,
-		Description: strings.ReplaceAll(v.Description, "\n", " "),
+// WithDeprecated configures the handling of deprecated operations, arguments, input fields,
+// response fields and enum values.
+func WithDeprecated(deprecated Deprecated) Option {
+	return func(opts *Options) {
+		opts.Deprecated = deprecated
 	}
-	for _, a := range v.Arguments {
-		tool.Args = append(tool.Args, parseArgs(a, schema))
-	}
-	tool.Query = getQuery(v, schema, resolverType)
-	return tool
 }
 
-func getQuery(v *ast.FieldDefinition, schema *Schema, resolverType ResolverType) string {
-	args := []string{}
-	queryInput := []string{}
+// WithSelectionLimits limits the selection set of the responses of all tools.
+func WithSelectionLimits(limits SelectionLimits) Option {
+	return func(opts *Options) {
+		opts.Limits = limits
+	}
+}
 
-	for _, f := range v.Arguments {
-		args = append(args, fmt.Sprintf("$%s: %s", f.Name, f.Type.String()))
-		queryInput = append(queryInput, fmt.Sprintf("%s: $%s", f.Name, f.Name))
+// WithToolSelectionLimits limits the selection set of the response of the tool with the given name,
+// the non-zero limits take precedence over the limits configured using WithSelectionLimits.
+func WithToolSelectionLimits(tool string, limits SelectionLimits) Option {
+	return func(opts *Options) {
+		if opts.ToolLimits == nil {
+			opts.ToolLimits = map[string]SelectionLimits{}
+		}
+		opts.ToolLimits[tool] = limits
 	}
-	indent := 4
-	responseQuery := getResponseQuery(v, schema, indent, map[string]bool{})
-	argumentsList := ""
-	if len(v.Arguments) > 0 {
-		argumentsList = fmt.Sprintf("(%s)", strings.Join(args, ", "))
+}
+
+// WithToolSelection overrides the selection set of the response of the tool with the given name,
+// the override is validated against the schema when generating the tool.
+func WithToolSelection(tool string, selection Selection) Option {
+	return func(opts *Options) {
+		if opts.Selections == nil {
+			opts.Selections = map[string]Selection{}
+		}
+		opts.Selections[tool] = selection
 	}
-	queryInputList := ""
-	if len(queryInput) > 0 {
-		queryInputList = fmt.Sprintf("(%s)", strings.Join(queryInput, ", "))
+}
+
+// WithCycleDepth expands the types which reference themselves, directly or through other types,
+// depth more times in the selection set of a response. By default such a type is not selected again.
+func WithCycleDepth(depth int) Option {
+	return func(opts *Options) {
+		opts.CycleDepth = depth
 	}
+}
 
-	query := fmt.Sprintf(`
-		%s %s %s {
-			%s%s %s
-		}
-	`, resolverType, v.Name, argumentsList, v.Name, queryInputList, responseQuery)
+// selectionLimits returns the limits of the selection set of the response of the tool.
+func (opts *Options) selectionLimits(tool string) SelectionLimits {
+	return opts.Limits.override(opts.ToolLimits[tool])
+}
 
-	return query
+// DefaultSubscriptionTimeout is the duration events of a subscription are collected, unless configured otherwise.
+const DefaultSubscriptionTimeout = 30 * time.Second
+
+// Subscriptions bounds the events collected by a tool executing a subscription. The tool returns the
+// collected events when either bound is reached or the subscription completes.
+type Subscriptions struct {
+	// MaxEvents is the maximum number of events, 0 means unlimited.
+	MaxEvents int
+	// Timeout is the maximum duration events are collected, defaults to DefaultSubscriptionTimeout.
+	Timeout time.Duration
+	// Progress sends an MCP progress notification for every event, if requested by the client.
+	Progress bool
 }
 
-func getResponseQuery(v *ast.FieldDefinition, schema *Schema, indent int, visited map[string]bool) string { //nolint:revive
-	if visited[v.Type.Name()] && schema.cyclicTypes[v.Type.Name()] {
-		return ""
-	}
-	visited[v.Type.Name()] = true
-	object, ok := schema.astSchema.Types[v.Type.Name()]
-	if !ok {
-		return ""
+// WithSubscriptionTools generates a tool for every subscription, which subscribes over a websocket
+// and returns the collected events.
+func WithSubscriptionTools() Option {
+	return func(opts *Options) {
+		opts.SubscriptionTools = true
 	}
+}
 
-	if object.Kind == ast.Union {
-		resp := "{\n"
-		resp += strings.Repeat("\t", indent) + "__typename\n"
-		for _, f := range object.Types {
-			object, ok := schema.astSchema.Types[f]
-			if !ok {
-				panic(fmt.Sprintf("Type %s not found in schema", f))
-			}
-			resp += strings.Repeat("\t", indent) + "... on " + f + " {\n"
-			indent++
-			resp += getResponseQueryForFields(object.Fields, schema, indent, visited)
+// WithSubscriptions bounds the events collected by the tools executing a subscription, including
+// the subscriptions in operation documents.
+func WithSubscriptions(subscriptions Subscriptions) Option {
+	return func(opts *Options) {
+		opts.Subscriptions = subscriptions
+	}
+}
 
-			indent--
-			resp += strings.Repeat("\t", indent) + "}\n"
-		}
+// subscriptions returns the bounds of the subscription tools, applying the default timeout.
+func (opts *Options) subscriptions() Subscriptions {
+	res := opts.Subscriptions
+	if res.Timeout == 0 {
+		res.Timeout = DefaultSubscriptionTimeout
+	}
+	return res
+}
 
-		resp += strings.Repeat("\t", indent-1) + "}\n"
-		return resp
+// WithOperations generates a tool for every named operation in the operation documents,
+// instead of a tool for every operation of the schema. The documents are validated against the schema.
+func WithOperations(sources ...*ast.Source) Option {
+	return func(opts *Options) {
+		opts.Operations = append(opts.Operations, sources...)
 	}
-	if len(object.Fields) == 0 {
-		return ""
+}
+
+// WithSkipInvalid skips the operations for which no tool can be generated,
+// warn is called with the *Error describing why the operation is skipped.
+func WithSkipInvalid(warn func(err error)) Option {
+	return func(opts *Options) {
+		opts.Warn = warn
 	}
+}
 
-	resp := "{\n"
-	resp += getResponseQueryForFields(object.Fields, schema, indent, visited)
-	resp += strings.Repeat("\t", indent-1) + "}\n"
-	return resp
+// Schema represents the schema to be used for generating tools.
+type Schema struct {
+	astSchema   *ast.Schema
+	cyclicTypes map[string]bool
+	cycleDepth  int
+	scalars     map[string]Scalar
+	deprecated  Deprecated
 }
 
-func getResponseQueryForFields(fields ast.FieldList, schema *Schema, indent int, visited map[string]bool) string {
-	result := ""
-	for _, f := range fields {
-		if visited[f.Type.Name()] && schema.cyclicTypes[f.Type.Name()] {
-			continue
-		}
-		result += strings.Repeat("\t", indent) + fmt.Sprintf("%s ", f.Name)
-		sub := getResponseQuery(f, schema, indent+1, visited)
-		if sub != "" {
-			result += sub
-		} else {
-			result += "\n"
-		}
+func newOptions(options []Option) *Options {
+	opts := &Options{}
+	for _, opt := range options {
+		opt(opts)
 	}
-	return result
+	return opts
 }
 
-func parseArgs(a *ast.ArgumentDefinition, schema *Schema) *ToolArg {
-	res := ToolArg{
-		Name:        a.Name,
-		Description: strings.ReplaceAll(a.Description, "\n", " "),
-		Required:    a.Type.NonNull,
+func newSchema(astSchema *ast.Schema, opts *Options) *Schema {
+	scalars := DefaultScalars()
+	for name, scalar := range opts.Scalars {
+		scalars[name] = scalar
+	}
+	return &Schema{
+		astSchema:   astSchema,
+		cyclicTypes: cyclicTypes(astSchema),
+		cycleDepth:  opts.CycleDepth,
+		scalars:     scalars,
+		deprecated:  opts.Deprecated,
 	}
-
-	return resolveToolArgType(&res, a.Type, schema)
 }
 
-func resolveToolArgType(res *ToolArg, t *ast.Type, schema *Schema) *ToolArg { //nolint:revive
-	toolType := graphQLTypeToToolType(t, schema)
-	res.Type = toolType
-	switch toolType {
-	case TypeString, TypeNumber, TypeBoolean:
-		return res
-	case TypeObject:
-		subType, ok := schema.astSchema.Types[t.Name()]
-		if !ok {
-			panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
+// GetToolsForSchema returns a tool for every query and mutation of the schema, and every subscription if
+// WithSubscriptionTools is used, or for every operation
+// in the documents configured using WithOperations. An *Error is returned
+// for every operation for which no tool can be generated, unless WithSkipInvalid is used.
+func GetToolsForSchema(astSchema *ast.Schema, options ...Option) ([]Tool, error) { //nolint:revive
+	opts := newOptions(options)
+	schema := newSchema(astSchema, opts)
+	b := &builder{opts: opts, known: knownTools(schema)}
+	if len(opts.Operations) > 0 {
+		operationTools(schema, opts.Operations, b)
+	}
+	if len(opts.Operations) == 0 && schema.astSchema.Query != nil {
+		for _, v := range schema.astSchema.Query.Fields {
+			if v.Name == "__schema" ||
+				v.Name == "__type" ||
+				v.Name == federation.EntitiesField ||
+				v.Name == federation.ServiceField {
+				continue
+			}
+			b.add(toolFromFieldDefinition(v, schema, opts, QueryResolver))
 		}
-		res.Properties = resolveObjectProperties(subType.Fields, schema)
-		return res
-	case TypeArray:
-		res.Items = resolveObjectProperties(arrayTypeToFieldList(t), schema)
-		return res
-	case TypeEnum:
-		subType, ok := schema.astSchema.Types[t.Name()]
-		if !ok {
-			panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
+	}
+	if len(opts.Operations) == 0 && schema.astSchema.Mutation != nil {
+		for _, v := range schema.astSchema.Mutation.Fields {
+			if v.Name == "__schema" ||
+				v.Name == "__type" {
+				continue
+			}
+			b.add(toolFromFieldDefinition(v, schema, opts, MutationResolver))
 		}
-		enumValues := subType.EnumValues
-		enums := []string{}
-		for _, enum := range enumValues {
-			enums = append(enums, enum.Name)
+	}
+	if len(opts.Operations) == 0 && opts.SubscriptionTools && schema.astSchema.Subscription != nil {
+		for _, v := range schema.astSchema.Subscription.Fields {
+			b.add(toolFromFieldDefinition(v, schema, opts, SubscriptionResolver))
 		}
-		res.Enum = enums
-		return res
-	default:
-		panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
 	}
+	if opts.EntityTools {
+		entityTools(schema, b)
+	}
+	b.checkConfiguredTools()
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	return b.tools, nil
 }
 
-func isArray(t *ast.Type) bool {
-	return t.NamedType == ""
+// builder collects the generated tools and errors.
+type builder struct {
+	opts  *Options
+	tools []Tool
+	errs  []error
+	// known contains the names of the tools of all operations, including the operations for which
+	// no tool is generated as they are filtered out or invalid.
+	known map[string]bool
 }
 
-// https://spec.graphql.org/draft/#sec-Type-System
-func graphQLTypeToToolType(t *ast.Type, schema *Schema) Type { //nolint:revive
-	if isArray(t) {
-		return TypeArray
+// add adds the tool, a nil tool is skipped as the operation is not selected. A tool with the
+// name of a tool which was added before results in an error.
+func (b *builder) add(tool *Tool, err error) {
+	if err == nil && tool != nil {
+		err = b.duplicate(tool)
 	}
-	switch t.Name() {
-	case "String", "ID", "DateTime":
-		return TypeString
-	case "Int", "Float":
-		return TypeNumber
-	case "Boolean":
-		return TypeBoolean
-	default:
-		subType, ok := schema.astSchema.Types[t.Name()]
-		if !ok {
-			panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
+	switch {
+	case err != nil && b.opts.Warn != nil:
+		b.opts.Warn(err)
+	case err != nil:
+		b.errs = append(b.errs, err)
+	case tool != nil:
+		b.tools = append(b.tools, *tool)
+	}
+}
+
+// checkConfiguredTools adds an error for every tool configured using WithToolSelectionLimits or
+// WithToolSelection which matches no operation of the schema or the operation documents.
+func (b *builder) checkConfiguredTools() {
+	names := []string{}
+	for name := range b.opts.ToolLimits {
+		names = append(names, name)
+	}
+	for name := range b.opts.Selections {
+		if _, ok := b.opts.ToolLimits[name]; !ok {
+			names = append(names, name)
 		}
-		switch subType.Kind {
-		case ast.Enum:
-			return TypeEnum
-		case ast.InputObject, ast.Object:
-			return TypeObject
-		case ast.Scalar, ast.Interface, ast.Union:
-			panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
-		default:
-			panic(fmt.Sprintf("Type %s not found in schema", t.Name()))
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if !b.known[name] {
+			b.add(nil, fmt.Errorf("%w: %s", ErrUnknownTool, name))
 		}
 	}
 }
 
-func resolveObjectProperties(fields ast.FieldList, schema *Schema) string { //nolint:revive
-	res := "map[string]any{"
-	subFields := []string{}
-	for _, f := range fields {
-		toolType := graphQLTypeToToolType(f.Type, schema)
-		subField := ""
-		if f.Name != "" {
-			subField += `"` + f.Name + `": map[string]any{`
+// knownTools returns the names of the tools of the operations of the schema and of the federated entities,
+// regardless of the filters and whether tools are generated for subscriptions and entities at all.
+func knownTools(schema *Schema) map[string]bool {
+	res := map[string]bool{}
+	for _, root := range []*ast.Definition{schema.astSchema.Query, schema.astSchema.Mutation, schema.astSchema.Subscription} {
+		if root == nil {
+			continue
+		}
+		for _, v := range root.Fields {
+			directive, err := toolDirectiveForField(v)
+			if err != nil {
+				res[v.Name] = true
+				continue
+			}
+			res[directive.name] = true
 		}
-		keyVals := []string{}
-		keyVals = append(keyVals, `"type": "`+toolType.PropertyDefinitionString()+`"`)
-		if f.Description != "" {
-			keyVals = append(keyVals, `"description": "`+strings.ReplaceAll(f.Description, "\n", " ")+`"`)
+	}
+	if entityUnion, ok := schema.astSchema.Types[federation.EntityUnion]; ok {
+		for _, name := range entityUnion.Types {
+			res[entityToolName(name)] = true
 		}
+	}
+	return res
+}
 
-		if toolType == TypeObject {
-			subType, ok := schema.astSchema.Types[f.Type.Name()]
-			if !ok {
-				panic(fmt.Sprintf("Type %s not found in schema", f.Type.Name()))
+// duplicate returns an error if a tool with the same name has been added for another operation.
+func (b *builder) duplicate(tool *Tool) error {
+	for _, t := range b.tools {
+		if t.Name == tool.Name {
+			return &Error{
+				OperationType: tool.OperationType,
+				Operation:     tool.Operation,
+				Err:           fmt.Errorf("%w %s, also used by %s %s", ErrDuplicateToolName, tool.Name, t.OperationType, t.Operation),
 			}
-			keyVals = append(keyVals, `"properties": `+resolveObjectProperties(subType.Fields, schema))
 		}
-		if toolType == TypeEnum {
-			subType, ok := schema.astSchema.Types[f.Type.Name()]
-			if !ok {
-				panic(fmt.Sprintf("Type %s not found in schema", f.Type.Name()))
-			}
-			enumValues := subType.EnumValues
-			enums := []string{}
-			for _, enum := range enumValues {
-				enums = append(enums, fmt.Sprintf(`"%s"`, enum.Name))
-			}
-			keyVals = append(keyVals, `"enum": `+fmt.Sprintf("[]string{%s}", strings.Join(enums, ", ")))
+	}
+	return nil
+}
+
+func toolFromFieldDefinition(v *ast.FieldDefinition, schema *Schema, opts *Options, resolverType ResolverType) (*Tool, error) {
+	directive, err := toolDirectiveForField(v)
+	if err != nil {
+		return nil, withOperation(err, resolverType, v.Name)
+	}
+	if directive.hidden || schema.excluded(v.Directives) || !opts.selected(directive.name, resolverType) {
+		return nil, nil
+	}
+	description, _ := schema.markDeprecated(strings.ReplaceAll(directive.description, "\n", " "), v.Directives)
+	tool := &Tool{
+		Name:          directive.name,
+		Description:   description,
+		OperationType: resolverType,
+		Operation:     v.Name,
+	}
+	if resolverType == SubscriptionResolver {
+		tool.Subscription = opts.subscriptions()
+	}
+	inputSchema := newInputSchemaBuilder(schema)
+	for _, a := range v.Arguments {
+		if schema.excludedArg(a.Type, a.DefaultValue, a.Directives) {
+			continue
 		}
-		if toolType == TypeArray {
-			keyVals = append(keyVals, `"items": `+resolveObjectProperties(arrayTypeToFieldList(f.Type), schema))
+		argSchema, err := inputSchema.argSchema(a.Type, a.Description, a.DefaultValue, a.Directives)
+		if err != nil {
+			return nil, withOperation(withPath(err, "$"+a.Name), resolverType, v.Name)
 		}
-		subField += strings.Join(keyVals, ", ")
-		if f.Name != "" {
-			subField += `}`
+		tool.Args = append(tool.Args, &ToolArg{
+			Name:     a.Name,
+			Required: isRequired(a.Type, a.DefaultValue),
+			Schema:   argSchema,
+		})
+	}
+	tool.Defs = inputSchema.defs
+	tool.Query, err = getQuery(v, directive.name, schema, resolverType, opts.selectionLimits(directive.name), opts.Selections[directive.name])
+	if err != nil {
+		return nil, withOperation(withPath(err, v.Name), resolverType, v.Name)
+	}
+	return tool, nil
+}
+
+func getQuery(v *ast.FieldDefinition, operationName string, schema *Schema, resolverType ResolverType, limits SelectionLimits, override Selection) (string, error) {
+	args := []string{}
+	queryInput := []string{}
+
+	for _, f := range v.Arguments {
+		if schema.excludedArg(f.Type, f.DefaultValue, f.Directives) {
+			continue
 		}
-		subFields = append(subFields, subField)
+		arg := fmt.Sprintf("$%s: %s", f.Name, f.Type.String())
+		// the default allows omitting non-null arguments which have a default value.
+		if f.DefaultValue != nil {
+			arg += " = " + f.DefaultValue.String()
+		}
+		args = append(args, arg)
+		queryInput = append(queryInput, fmt.Sprintf("%s: $%s", f.Name, f.Name))
 	}
-	res += strings.Join(subFields, ", ")
-	res += "}"
-	return res
+	indent := 4
+	responseQuery, err := getResponseQuery(v.Type.Name(), schema, indent, limits, override)
+	if err != nil {
+		return "", err
+	}
+	argumentsList := ""
+	if len(args) > 0 {
+		argumentsList = fmt.Sprintf("(%s)", strings.Join(args, ", "))
+	}
+	queryInputList := ""
+	if len(queryInput) > 0 {
+		queryInputList = fmt.Sprintf("(%s)", strings.Join(queryInput, ", "))
+	}
+
+	query := fmt.Sprintf(`
+		%s %s %s {
+			%s%s %s
+		}
+	`, resolverType, operationName, argumentsList, v.Name, queryInputList, responseQuery)
+
+	return query, nil
 }
 
-func arrayTypeToFieldList(t *ast.Type) ast.FieldList {
-	arrayType := &ast.Type{
-		Elem:      t,
-		NamedType: t.Elem.Name(),
-		Position:  t.Position,
+// getResponseQuery returns the selection set of the response of a field of the given type within
+// the limits or as configured by the override, which is empty for leaf types.
+func getResponseQuery(typeName string, schema *Schema, indent int, limits SelectionLimits, override Selection) (string, error) {
+	selectionSet, err := responseSelectionSet(typeName, schema, limits, override)
+	if err != nil {
+		return "", err
 	}
-	return ast.FieldList{
-		&ast.FieldDefinition{
-			Type: arrayType,
-		},
+	if len(selectionSet) == 0 && !isLeafType(typeName, schema) {
+		return "", typeError(typeName, ErrEmptySelection)
 	}
+	return renderSelectionSet(selectionSet, indent), nil
+}
+
+func isArray(t *ast.Type) bool {
+	return t.NamedType == ""
 }
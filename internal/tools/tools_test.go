@@ -1,12 +1,18 @@
 package tools
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/federation"
 )
 
 func TestSimpleQuery(t *testing.T) {
@@ -33,24 +39,26 @@ type Query {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
 	assert.Equal(t, "example", tools[0].Name)
 	assert.Equal(t, "Retrieves a list of examples.", tools[0].Description)
 	assert.Equal(t, 4, len(tools[0].Args))
-	assert.Equal(t, "some string arg.", tools[0].Args[0].Description)
-	assert.Equal(t, "some id.", tools[0].Args[1].Description)
-	assert.Equal(t, "some int.", tools[0].Args[2].Description)
-	assert.Equal(t, "some bool.", tools[0].Args[3].Description)
 	assert.Equal(t, "string", tools[0].Args[0].Name)
 	assert.Equal(t, "id", tools[0].Args[1].Name)
 	assert.Equal(t, "int", tools[0].Args[2].Name)
 	assert.Equal(t, "bool", tools[0].Args[3].Name)
-	assert.Equal(t, TypeString, tools[0].Args[0].Type)
-	assert.Equal(t, TypeString, tools[0].Args[1].Type)
-	assert.Equal(t, TypeNumber, tools[0].Args[2].Type)
-	assert.Equal(t, TypeBoolean, tools[0].Args[3].Type)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"string": {"type": ["string", "null"], "description": "some string arg."},
+			"id": {"type": ["string", "null"], "description": "some id."},
+			"int": {"type": ["integer", "null"], "description": "some int."},
+			"bool": {"type": ["boolean", "null"], "description": "some bool."}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
 }
 
 func TestQueryWithInputObject(t *testing.T) {
@@ -76,16 +84,29 @@ type Query {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
 	assert.Equal(t, "example", tools[0].Name)
 	assert.Equal(t, "Retrieves a list of examples.", tools[0].Description)
 	assert.Equal(t, 1, len(tools[0].Args))
-	assert.Equal(t, "a filter.", tools[0].Args[0].Description)
 	assert.Equal(t, "filter", tools[0].Args[0].Name)
-	assert.Equal(t, TypeObject, tools[0].Args[0].Type)
-	assert.Equal(t, `map[string]any{"id": map[string]any{"type": "string", "description": "Filter by ID."}}`, tools[0].Args[0].Properties)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/Filter"}, {"type": "null"}], "description": "a filter."}
+		},
+		"$defs": {
+			"Filter": {
+				"type": "object",
+				"description": "Filter for listing examples.",
+				"properties": {
+					"id": {"type": ["string", "null"], "description": "Filter by ID."}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
 }
 
 func TestQueryWithInputObjectArray(t *testing.T) {
@@ -116,13 +137,29 @@ type Query {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
-	assert.Equal(t, TypeArray, tools[0].Args[0].Type)
-	assert.Equal(t,
-		`map[string]any{"type": "object", "properties": map[string]any{"title": map[string]any{"type": "string", "description": "Title of the book."}, "author": map[string]any{"type": "array", "description": "Author of the book.", "items": map[string]any{"type": "string"}}, "year": map[string]any{"type": "number", "description": "Year of publication."}}}`,
-		tools[0].Args[0].Items)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"type": "array", "description": "a filter.", "items": {"anyOf": [{"$ref": "#/$defs/Book"}, {"type": "null"}]}}
+		},
+		"required": ["filter"],
+		"$defs": {
+			"Book": {
+				"type": "object",
+				"description": "Book object.",
+				"properties": {
+					"title": {"type": "string", "description": "Title of the book."},
+					"author": {"type": "array", "description": "Author of the book.", "items": {"type": ["string", "null"]}},
+					"year": {"type": ["integer", "null"], "description": "Year of publication."}
+				},
+				"required": ["title", "author"]
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
 }
 
 func TestEnumInInputObject(t *testing.T) {
@@ -155,13 +192,123 @@ type Query {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
-	assert.Equal(t, TypeObject, tools[0].Args[0].Type)
-	assert.Equal(t,
-		`map[string]any{"type": map[string]any{"type": "string", "description": "Type of the book.", "enum": []string{"Hardcover", "Paperback"}}}`,
-		tools[0].Args[0].Properties)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/Book"}, {"type": "null"}], "description": "a filter."}
+		},
+		"$defs": {
+			"Book": {
+				"type": "object",
+				"properties": {
+					"type": {
+						"type": "string",
+						"description": "Type of the book.",
+						"enum": ["Hardcover", "Paperback"],
+						"oneOf": [
+							{"const": "Hardcover", "description": "Hardcover book."},
+							{"const": "Paperback", "description": "Paperback book."}
+						]
+					}
+				},
+				"required": ["type"]
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+}
+
+func TestRecursiveInputObject(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+input Filter {
+  title: String
+  and: [Filter!]
+  not: Filter
+}
+
+type Query {
+  books(filter: Filter, orFilter: [Filter!]): [String!]!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/Filter"}, {"type": "null"}]},
+			"orFilter": {"type": ["array", "null"], "items": {"$ref": "#/$defs/Filter"}}
+		},
+		"$defs": {
+			"Filter": {
+				"type": "object",
+				"properties": {
+					"title": {"type": ["string", "null"]},
+					"and": {"type": ["array", "null"], "items": {"$ref": "#/$defs/Filter"}},
+					"not": {"anyOf": [{"$ref": "#/$defs/Filter"}, {"type": "null"}]}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+}
+
+func TestDefaultValues(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+enum SortOrder {
+  ASC
+  DESC
+}
+
+input Page {
+  size: Int! = 20
+  order: SortOrder = DESC
+  tags: [String!] = []
+}
+
+type Query {
+  books(first: Int! = 10, title: String = "Dune", page: Page = {size: 5}, available: Boolean!): [String!]!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"first": {"type": "integer", "default": 10},
+			"title": {"type": ["string", "null"], "default": "Dune"},
+			"page": {"anyOf": [{"$ref": "#/$defs/Page"}, {"type": "null"}], "default": {"size": 5}},
+			"available": {"type": "boolean"}
+		},
+		"required": ["available"],
+		"$defs": {
+			"Page": {
+				"type": "object",
+				"properties": {
+					"size": {"type": "integer", "default": 20},
+					"order": {"type": ["string", "null"], "enum": ["ASC", "DESC", null], "default": "DESC"},
+					"tags": {"type": ["array", "null"], "items": {"type": "string"}, "default": []}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+	compareQueries(t,
+		`query books ($first: Int! = 10, $title: String = "Dune", $page: Page = {size:5}, $available: Boolean!) {
+			books(first: $first, title: $title, page: $page, available: $available)
+		}`, tools[0].Query)
 }
 
 func TestEnum(t *testing.T) {
@@ -190,11 +337,25 @@ type Query {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
-	assert.Equal(t, TypeEnum, tools[0].Args[0].Type)
-	assert.Equal(t, []string{"Hardcover", "Paperback"}, tools[0].Args[0].Enum)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {
+				"type": ["string", "null"],
+				"description": "a filter.",
+				"enum": ["Hardcover", "Paperback", null],
+				"oneOf": [
+					{"const": "Hardcover", "description": "Hardcover book."},
+					{"const": "Paperback", "description": "Paperback book."},
+					{"type": "null"}
+				]
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
 }
 
 func TestUnion(t *testing.T) {
@@ -231,7 +392,8 @@ func TestUnion(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
 
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 
 	assert.Equal(t, 1, len(tools))
@@ -275,6 +437,11 @@ type Droid implements Character {
   primaryFunction: String
 }
 
+type Extra implements Character {
+  id: ID!
+  name: String!
+}
+
 "Query root."
 	type Query {
 	"Retrieves a list of characters."
@@ -290,17 +457,74 @@ type Droid implements Character {
 	})
 	assert.NoError(t, err)
 	assert.NotNil(t, schema)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
 	compareQueries(t,
 		`query example ($filter: String) {
                         example(filter: $filter) {
+                                __typename
                                 id 
                                 name 
+                                ... on Human {
+                                        totalCredits
+                                }
+                                ... on Droid {
+                                        primaryFunction
+                                }
                         }
 
                 }`, tools[0].Query)
+
+	schema, err = gqlparser.LoadSchema(&ast.Source{
+		Input: `
+interface Node {
+  id: ID!
+}
+
+type Book implements Node {
+  id: ID!
+  name: String
+  rating: Int
+  isbn: String!
+}
+
+type Author implements Node {
+  id: ID!
+  name: String!
+  rating: Float
+  isbn: String!
+}
+
+type Query {
+  node(id: ID!): Node
+}`,
+	})
+	assert.NoError(t, err)
+	tools, err = GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	compareQueries(t,
+		`query node ($id: ID!) {
+			node(id: $id) {
+				__typename
+				id
+				... on Book {
+					Book_name: name
+					Book_rating: rating
+					isbn
+				}
+				... on Author {
+					Author_name: name
+					Author_rating: rating
+					isbn
+				}
+			}
+		}`, tools[0].Query)
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Input: tools[0].Query})
+	assert.NoError(t, gqlErr)
+	assert.Empty(t, validator.Validate(schema, doc))
 }
 
 func TestQueryForCyclicSchema(t *testing.T) {
@@ -345,7 +569,8 @@ type Book implements Node {
 		Input: gqlSchma,
 	})
 	assert.NoError(t, err)
-	tools := GetToolsForSchema(schema)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
 	assert.NotNil(t, tools)
 	assert.Equal(t, 1, len(tools))
 	compareQueries(t,
@@ -362,6 +587,204 @@ type Book implements Node {
                 }`, tools[0].Query)
 }
 
+func TestRequiredFieldArguments(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Meta {
+  a: String!
+  b(format: String): String
+  c(limit: Int! = 10): [String!]!
+  d(x: Int!): Int
+}
+
+type Book {
+  id: ID!
+  meta: Meta!
+  related(first: Int!): [Book!]!
+}
+
+type Query {
+  book(id: ID!): Book
+}`,
+	})
+	assert.NoError(t, err)
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	compareQueries(t,
+		`query book ($id: ID!) {
+			book(id: $id) {
+				id
+				meta {
+					a
+					b
+					c
+				}
+			}
+		}`, tools[0].Query)
+	doc, gqlErr := parser.ParseQuery(&ast.Source{Input: tools[0].Query})
+	assert.NoError(t, gqlErr)
+	assert.Empty(t, validator.Validate(schema, doc))
+}
+
+func TestCyclicTypes(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Person {
+	name: String!
+	friend: Person
+	pet: Pet
+}
+
+type Pet {
+	owner: Owner!
+	tag: Tag
+}
+
+union Owner = Person | Shelter
+
+type Shelter {
+	name: String!
+}
+
+type Tag {
+	node: Tag2
+}
+
+type Tag2 {
+	node: Shelter
+}
+
+type Query {
+	person: Person
+}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"Person": true,
+		"Pet":    true,
+		"Owner":  true,
+	}, cyclicTypes(schema))
+}
+
+func TestCycleDepth(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Person {
+	name: String!
+	friend: Person
+	book: Book
+}
+
+type Book {
+	title: String!
+	author: Person!
+}
+
+type Pair {
+	left: Person!
+	right: Person!
+}
+
+type Query {
+	pair: Pair!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		options  []Option
+		expected string
+	}{
+		{
+			name: "default",
+			expected: `query pair {
+	pair {
+		left {
+			name
+			book {
+				title
+			}
+		}
+		right {
+			name
+			book {
+				title
+			}
+		}
+	}
+}`,
+		},
+		{
+			name:    "one level",
+			options: []Option{WithCycleDepth(1)},
+			expected: `query pair {
+	pair {
+		left {
+			name
+			friend {
+				name
+				book {
+					title
+				}
+			}
+			book {
+				title
+				author {
+					name
+					book {
+						title
+					}
+				}
+			}
+		}
+		right {
+			name
+			friend {
+				name
+				book {
+					title
+				}
+			}
+			book {
+				title
+				author {
+					name
+					book {
+						title
+					}
+				}
+			}
+		}
+	}
+}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			tools, err := GetToolsForSchema(schema, test.options...)
+			assert.NoError(t, err)
+			assert.Len(t, tools, 1)
+			compareQueries(t, test.expected, tools[0].Query)
+		})
+	}
+}
+
+func inputSchemaJSON(t *testing.T, tool Tool) string {
+	t.Helper()
+	b, err := json.Marshal(tool.InputSchema())
+	assert.NoError(t, err)
+	return string(b)
+}
+
 func compareQueries(t *testing.T, expected, actual string) {
 	t.Helper()
 	expected = strings.ReplaceAll(expected, "\n", "")
@@ -374,3 +797,1063 @@ func compareQueries(t *testing.T, expected, actual string) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestSelectionLimits(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Publisher {
+  name: String!
+  country: Country!
+}
+
+type Country {
+  code: String!
+  name: String!
+}
+
+type Author {
+  name: String!
+  publisher: Publisher
+}
+
+type Book {
+  author: Author!
+  id: ID!
+  title: String!
+  publisher: Publisher
+  price: Float
+}
+
+type Query {
+  books: [Book!]!
+  book: Book
+  authorPublisher: Publisher
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema,
+		WithSelectionLimits(SelectionLimits{MaxDepth: 2}),
+		WithToolSelectionLimits("book", SelectionLimits{MaxFields: 7}),
+		WithToolSelectionLimits("authorPublisher", SelectionLimits{MaxDepth: 1, MaxFields: 1}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(tools))
+	compareQueries(t,
+		`query books {
+			books {
+				author {
+					name
+				}
+				id
+				title
+				publisher {
+					name
+				}
+				price
+			}
+		}`, tools[0].Query)
+	compareQueries(t,
+		`query book {
+			book {
+				author {
+					name
+				}
+				id
+				title
+				publisher {
+					name
+				}
+				price
+			}
+		}`, tools[1].Query)
+	compareQueries(t,
+		`query authorPublisher {
+			authorPublisher {
+				name
+			}
+		}`, tools[2].Query)
+
+	schema, err = gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Shelf {
+  book: Book!
+}
+
+type Book {
+  title: String!
+}
+
+type Query {
+  shelf: Shelf!
+}`,
+	})
+	assert.NoError(t, err)
+	_, err = GetToolsForSchema(schema, WithSelectionLimits(SelectionLimits{MaxDepth: 1}))
+	assert.ErrorIs(t, err, ErrEmptySelection)
+	assert.EqualError(t, err, "query shelf, shelf, type Shelf: no fields can be selected within the selection limits")
+}
+
+func TestSelectionLimitsWideGraph(t *testing.T) {
+	t.Parallel()
+
+	// every type references all the types after it, such that the number of paths through the
+	// graph doubles with every type.
+	var input strings.Builder
+	types := 40
+	for i := 1; i <= types; i++ {
+		fmt.Fprintf(&input, "type T%d {\n  id: ID!\n", i)
+		for j := i + 1; j <= types; j++ {
+			fmt.Fprintf(&input, "  t%d: T%d\n", j, j)
+		}
+		input.WriteString("}\n")
+	}
+	input.WriteString("type Query {\n  t1: T1\n}\n")
+	schema, err := gqlparser.LoadSchema(&ast.Source{Input: input.String()})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema, WithSelectionLimits(SelectionLimits{MaxDepth: 2}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, types, strings.Count(tools[0].Query, "id"))
+
+	tools, err = GetToolsForSchema(schema, WithSelectionLimits(SelectionLimits{MaxFields: 7}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	compareQueries(t,
+		`query t1 {
+			t1 {
+				id
+				t2 {
+					id
+				}
+				t3 {
+					id
+				}
+				t4 {
+					id
+				}
+			}
+		}`, tools[0].Query)
+}
+
+func TestToolSelection(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Author {
+  name: String!
+  biography: String
+  books: [Book!]!
+}
+
+type Book {
+  id: ID!
+  title: String!
+  price: Float
+  author: Author!
+}
+
+type Magazine {
+  title: String!
+  issue: Int!
+}
+
+union SearchResult = Book | Magazine
+
+type Query {
+  books: [Book!]!
+  book: Book
+  author: Author
+  search: [SearchResult!]!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema,
+		WithToolSelection("books", Selection{Fields: "id title price author { name }"}),
+		WithToolSelection("book", Selection{Exclude: []string{"author.biography", "price"}}),
+		WithToolSelection("author", Selection{Include: []string{"name", "books.title"}}),
+		WithToolSelection("search", Selection{Include: []string{"title", "issue"}}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(tools))
+	compareQueries(t,
+		`query books {
+			books {
+				id
+				title
+				price
+				author {
+					name
+				}
+			}
+		}`, tools[0].Query)
+	compareQueries(t,
+		`query book {
+			book {
+				id
+				title
+				author {
+					name
+				}
+			}
+		}`, tools[1].Query)
+	compareQueries(t,
+		`query author {
+			author {
+				name
+				books {
+					title
+				}
+			}
+		}`, tools[2].Query)
+	compareQueries(t,
+		`query search {
+			search {
+				__typename
+				... on Book {
+					title
+				}
+				... on Magazine {
+					title
+					issue
+				}
+			}
+		}`, tools[3].Query)
+
+	tests := []struct {
+		name      string
+		selection Selection
+		err       error
+		expected  string
+	}{
+		{
+			name:      "unknown field",
+			selection: Selection{Fields: "id author { nme }"},
+			err:       ErrFieldNotFound,
+			expected:  "query book, book.author.nme, type Author: field not found",
+		},
+		{
+			name:      "missing selection set",
+			selection: Selection{Fields: "author"},
+			err:       ErrInvalidSelection,
+			expected:  "query book, book.author, type Author: invalid selection set: objects, interfaces and unions require a selection set",
+		},
+		{
+			name:      "arguments",
+			selection: Selection{Fields: "id(format: 1)"},
+			err:       ErrInvalidSelection,
+			expected:  "query book, book.id, type Book: invalid selection set: aliases, arguments and directives are not supported",
+		},
+		{
+			name:      "syntax error",
+			selection: Selection{Fields: "id {"},
+			err:       ErrInvalidSelection,
+			expected:  `query book, book, type Book: invalid selection set "id {": input:1: expected at least one definition, found }`,
+		},
+		{
+			name:      "unknown path",
+			selection: Selection{Include: []string{"author.age"}},
+			err:       ErrFieldNotFound,
+			expected:  "query book, book.author.age, type Author: field not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := GetToolsForSchema(schema, WithToolSelection("book", test.selection))
+			assert.ErrorIs(t, err, test.err)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+
+	_, err = GetToolsForSchema(schema, WithToolSelection("bookz", Selection{Fields: "nonexistent { bogus }"}),
+		WithToolSelectionLimits("authorz", SelectionLimits{MaxDepth: 1}))
+	assert.ErrorIs(t, err, ErrUnknownTool)
+	assert.EqualError(t, err, "configured tool matches no operation: authorz\nconfigured tool matches no operation: bookz")
+
+	// tools of which the generation fails are not reported as unknown.
+	renamed, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+directive @mcpTool(name: String, description: String, hidden: Boolean) on FIELD_DEFINITION
+
+type Shelf {
+  book: Shelf
+}
+
+type Query {
+  shelf: Shelf @mcpTool(name: "getShelf")
+}`,
+	})
+	assert.NoError(t, err)
+	_, err = GetToolsForSchema(renamed, WithToolSelectionLimits("getShelf", SelectionLimits{MaxDepth: 1}))
+	assert.ErrorIs(t, err, ErrEmptySelection)
+	assert.EqualError(t, err, "query shelf, shelf, type Shelf: no fields can be selected within the selection limits")
+}
+
+func TestOperations(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Author {
+  id: ID!
+  name: String!
+  books: [Book!]!
+}
+
+type Book {
+  id: ID!
+  title: String!
+  author: Author!
+}
+
+type Query {
+  book(id: ID!): Book
+  books(limit: Int): [Book!]!
+}
+
+type Mutation {
+  deleteBook(id: ID!): Boolean!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema, WithOperations(
+		&ast.Source{Name: "books.graphql", Input: `
+# Retrieves a book together with its author
+# and related books.
+query bookWithAuthorAndRelated(
+	# The ID of the book.
+	$id: ID!
+	$limit: Int = 5
+) {
+	book(id: $id) {
+		...BookFields
+		author {
+			name
+		}
+	}
+	books(limit: $limit) {
+		...BookFields
+	}
+}
+
+mutation deleteBook($id: ID!) {
+	deleteBook(id: $id)
+}`},
+		&ast.Source{Name: "fragments.graphql", Input: `
+fragment BookFields on Book {
+	id
+	title
+}`},
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	assert.Equal(t, "bookWithAuthorAndRelated", tools[0].Name)
+	assert.Equal(t, "Retrieves a book together with its author and related books.", tools[0].Description)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "The ID of the book."},
+			"limit": {"type": ["integer", "null"], "default": 5}
+		},
+		"required": ["id"]
+	}`, inputSchemaJSON(t, tools[0]))
+	compareQueries(t,
+		`query bookWithAuthorAndRelated ($id: ID!, $limit: Int = 5) {
+			book(id: $id) {
+				... BookFields
+				author {
+					name
+				}
+			}
+			books(limit: $limit) {
+				... BookFields
+			}
+		}
+		fragment BookFields on Book {
+			id
+			title
+		}`, tools[0].Query)
+	assert.Equal(t, "deleteBook", tools[1].Name)
+	assert.Equal(t, "Executes the mutation operation deleteBook.", tools[1].Description)
+
+	tools, err = GetToolsForSchema(schema, WithQueriesOnly(), WithOperations(&ast.Source{Name: "books.graphql", Input: `
+query book($id: ID!) { book(id: $id) { title } }
+mutation deleteBook($id: ID!) { deleteBook(id: $id) }`}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, "book", tools[0].Name)
+
+	_, err = GetToolsForSchema(schema, WithOperations(&ast.Source{Name: "books.graphql", Input: `
+query books { books { isbn } }`}))
+	assert.EqualError(t, err, `invalid operation document: books.graphql:2: Cannot query field "isbn" on type "Book".`)
+
+	_, err = GetToolsForSchema(schema, WithOperations(&ast.Source{Name: "books.graphql", Input: `
+{ books { id } }`}))
+	assert.ErrorIs(t, err, ErrAnonymousOperation)
+	assert.EqualError(t, err, "query books.graphql:2: operations must be named to be used as tool")
+
+	_, err = GetToolsForSchema(schema, WithToolSelectionLimits("book", SelectionLimits{MaxDepth: 1}),
+		WithOperations(&ast.Source{Name: "books.graphql", Input: `
+query book($id: ID!) { book(id: $id) { title } }`}))
+	assert.ErrorIs(t, err, ErrOperationSelection)
+	assert.EqualError(t, err, "query book: selection limits and selections can't be configured for operation documents, change the document instead")
+}
+
+func TestSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Book {
+  id: ID!
+  title: String!
+}
+
+type Query {
+  books: [Book!]!
+}
+
+type Subscription {
+  "Notifies about added books."
+  bookAdded(genre: String): Book!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, QueryResolver, tools[0].OperationType)
+
+	tools, err = GetToolsForSchema(schema, WithSubscriptionTools(), WithSubscriptions(Subscriptions{MaxEvents: 5, Progress: true}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	assert.Equal(t, "bookAdded", tools[1].Name)
+	assert.Equal(t, "Notifies about added books.", tools[1].Description)
+	assert.Equal(t, SubscriptionResolver, tools[1].OperationType)
+	assert.Equal(t, Subscriptions{MaxEvents: 5, Timeout: DefaultSubscriptionTimeout, Progress: true}, tools[1].Subscription)
+	compareQueries(t,
+		`subscription bookAdded ($genre: String) {
+			bookAdded(genre: $genre) {
+				id
+				title
+			}
+		}`, tools[1].Query)
+
+	tools, err = GetToolsForSchema(schema, WithSubscriptionTools(), WithQueriesOnly())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+
+	tools, err = GetToolsForSchema(schema, WithOperations(&ast.Source{Name: "books.graphql", Input: `
+subscription titles { bookAdded { title } }`}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, SubscriptionResolver, tools[0].OperationType)
+	assert.Equal(t, DefaultSubscriptionTimeout, tools[0].Subscription.Timeout)
+}
+
+func TestResources(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+interface Node {
+  id: ID!
+}
+
+"Represents a book in the store."
+type Book implements Node {
+  id: ID!
+  title: String!
+  author: Author!
+}
+
+type Author implements Node {
+  id: ID!
+  name: String!
+  books: [Book!]!
+}
+
+type Query {
+  book(id: ID!): Book
+  books(title: String!): [Book!]!
+  node(id: ID!): Node
+}`,
+	})
+	assert.NoError(t, err)
+
+	resources, err := GetResourcesForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resources))
+
+	assert.Equal(t, "Book", resources[0].TypeName)
+	assert.Equal(t, "Book by its id. Represents a book in the store.", resources[0].Description)
+	assert.Equal(t, "id", resources[0].Variable)
+	assert.Equal(t, "bookResource", resources[0].OperationName)
+	compareQueries(t,
+		`query bookResource ($id: ID!) {
+			book(id: $id) {
+				id
+				title
+				author {
+					id
+					name
+				}
+			}
+		}`, resources[0].Query)
+
+	assert.Equal(t, "Author", resources[1].TypeName)
+	assert.Equal(t, "authorResource", resources[1].OperationName)
+	compareQueries(t,
+		`query authorResource ($id: ID!) {
+			node(id: $id) {
+				__typename
+				... on Author {
+					id
+					name
+					books {
+						id
+						title
+					}
+				}
+			}
+		}`, resources[1].Query)
+
+	resources, err = GetResourcesForSchema(schema, WithToolSelection("book", Selection{Fields: "title"}))
+	assert.NoError(t, err)
+	compareQueries(t,
+		`query bookResource ($id: ID!) {
+			book(id: $id) {
+				title
+			}
+		}`, resources[0].Query)
+}
+
+func TestResourcesSelection(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+interface Node {
+  id: ID!
+}
+
+type User implements Node {
+  id: ID!
+  name: String!
+  email: String!
+}
+
+type Book implements Node {
+  id: ID!
+  title: String!
+}
+
+type Query {
+  adminUser(id: ID!): User @mcpTool(hidden: true)
+  secretBook(id: ID!): Book
+  node(id: ID!): Node
+}`,
+	}
+	prelude, err := Prelude(source)
+	assert.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+	exclude := func(p ...string) Option {
+		patterns, err := ParsePatterns(p...)
+		assert.NoError(t, err)
+		return WithExclude(patterns...)
+	}
+	resourceQueries := func(options ...Option) map[string]string {
+		resources, err := GetResourcesForSchema(schema, options...)
+		assert.NoError(t, err)
+		res := map[string]string{}
+		for _, r := range resources {
+			doc, err := parser.ParseQuery(&ast.Source{Input: r.Query})
+			assert.NoError(t, err)
+			res[r.TypeName] = doc.Operations[0].SelectionSet[0].(*ast.Field).Name
+		}
+		return res
+	}
+
+	assert.Equal(t, map[string]string{"Book": "secretBook", "User": "node"}, resourceQueries())
+	assert.Equal(t, map[string]string{"Book": "node", "User": "node"}, resourceQueries(exclude("secretBook")))
+	assert.Equal(t, map[string]string{"Book": "secretBook"}, resourceQueries(exclude("node")))
+	assert.Equal(t, map[string]string{}, resourceQueries(exclude("secretBook", "node")))
+
+	resources, err := GetResourcesForSchema(schema, exclude("secretBook"),
+		WithToolSelectionLimits("node", SelectionLimits{MaxFields: 1}),
+		WithToolSelection("node", Selection{Exclude: []string{"id"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resources))
+	compareQueries(t,
+		`query userResource ($id: ID!) {
+			node(id: $id) {
+				__typename
+				... on User {
+					name
+				}
+			}
+		}`, resources[0].Query)
+	compareQueries(t,
+		`query bookResource ($id: ID!) {
+			node(id: $id) {
+				__typename
+				... on Book {
+					title
+				}
+			}
+		}`, resources[1].Query)
+
+	resources, err = GetResourcesForSchema(schema, exclude("secretBook"),
+		WithToolSelection("node", Selection{Fields: "id ... on Book { title }"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resources))
+	compareQueries(t,
+		`query userResource ($id: ID!) {
+			node(id: $id) {
+				id
+				... on Book {
+					title
+				}
+			}
+		}`, resources[0].Query)
+}
+
+func TestEntityTools(t *testing.T) {
+	t.Parallel()
+
+	schema, err := federation.LoadSchema(&ast.Source{
+		Input: `
+"Represents a book in the store."
+type Book @key(fields: "id") {
+  "The unique identifier for the book."
+  id: ID!
+  title: String!
+}
+
+type Query {
+  "Retrieves a list of books."
+  books: [Book!]!
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, "books", tools[0].Name)
+
+	tools, err = GetToolsForSchema(schema, WithEntityTools())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	assert.Equal(t, "lookupBook", tools[1].Name)
+	assert.Equal(t, "Look up Book entities by one of their keys: id. Represents a book in the store.", tools[1].Description)
+	assert.Equal(t, "representations", tools[1].Args[0].Name)
+	assert.True(t, tools[1].Args[0].Required)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"representations": {
+				"type": "array",
+				"description": "The key fields of the Book entities to look up.",
+				"items": {
+					"type": "object",
+					"properties": {
+						"__typename": {"type": "string", "enum": ["Book"]},
+						"id": {"type": "string", "description": "The unique identifier for the book."}
+					},
+					"required": ["__typename", "id"]
+				}
+			}
+		},
+		"required": ["representations"]
+	}`, inputSchemaJSON(t, tools[1]))
+	compareQueries(t,
+		`query lookupBook ($representations: [_Any!]!) {
+			_entities(representations: $representations) {
+				__typename
+				... on Book {
+					id
+					title
+				}
+			}
+		}`, tools[1].Query)
+
+	tools, err = GetToolsForSchema(schema, WithEntityTools(), WithToolSelection("lookupBook", Selection{Exclude: []string{"title"}}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	compareQueries(t,
+		`query lookupBook ($representations: [_Any!]!) {
+			_entities(representations: $representations) {
+				__typename
+				... on Book {
+					id
+				}
+			}
+		}`, tools[1].Query)
+
+	_, err = GetToolsForSchema(schema, WithEntityTools(), WithToolSelection("lookupBook", Selection{Fields: "id author"}))
+	assert.ErrorIs(t, err, ErrFieldNotFound)
+	assert.EqualError(t, err, "query lookupBook, _entities.author, type Book: field not found")
+}
+
+func TestFilters(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+type Query {
+  book(id: ID!): String
+  books: [String!]!
+  author(id: ID!): String
+}
+
+type Mutation {
+  createBook(title: String!): String
+  deleteBook(id: ID!): Boolean
+}`,
+	})
+	assert.NoError(t, err)
+
+	toolNames := func(options ...Option) []string {
+		names := []string{}
+		tools, err := GetToolsForSchema(schema, options...)
+		assert.NoError(t, err)
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	patterns := func(p ...string) []Pattern {
+		res, err := ParsePatterns(p...)
+		assert.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, []string{"book", "books", "author", "createBook", "deleteBook"}, toolNames())
+	assert.Equal(t, []string{"book", "books", "author"}, toolNames(WithQueriesOnly()))
+	assert.Equal(t, []string{"book", "books", "author"}, toolNames(WithoutMutations()))
+	assert.Equal(t, []string{"book", "books", "author"}, toolNames(WithoutMutations(), WithToolSelectionLimits("createBook", SelectionLimits{MaxDepth: 1})))
+	assert.Equal(t, []string{"book", "books"}, toolNames(WithInclude(patterns("book*")...)))
+	assert.Equal(t, []string{"author", "createBook"}, toolNames(WithInclude(patterns("author", "/^create/")...)))
+	assert.Equal(t, []string{"book", "books", "author", "createBook"}, toolNames(WithExclude(patterns("delete*")...)))
+	assert.Equal(t, []string{"createBook"}, toolNames(WithInclude(patterns("mutation.*")...), WithExclude(patterns("deleteBook")...)))
+
+	_, err = ParsePatterns("/[/")
+	assert.ErrorContains(t, err, "invalid regular expression /[/")
+	_, err = ParsePatterns("[")
+	assert.ErrorContains(t, err, "invalid pattern [")
+}
+
+func TestToolDirectives(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+input BookFilter {
+  "Title of the book."
+  title: String @mcpArg(description: "Part of the title of the book.")
+}
+
+type Query {
+  "Retrieves a list of books."
+  books(
+    "a filter."
+    filter: BookFilter @mcpArg(description: "Filter the books.")
+    "Number of books."
+    first: Int
+  ): [String!]! @mcpTool(name: "search_books", description: "Search the books in the store.")
+  "Internal query."
+  stats: String @mcpTool(hidden: true)
+}`,
+	}
+	prelude, err := Prelude(source)
+	assert.NoError(t, err)
+	assert.NotNil(t, prelude)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, "search_books", tools[0].Name)
+	assert.Equal(t, "Search the books in the store.", tools[0].Description)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/BookFilter"}, {"type": "null"}], "description": "Filter the books."},
+			"first": {"type": ["integer", "null"], "description": "Number of books."}
+		},
+		"$defs": {
+			"BookFilter": {
+				"type": "object",
+				"properties": {
+					"title": {"type": ["string", "null"], "description": "Part of the title of the book."}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+	compareQueries(t,
+		`query search_books ($filter: BookFilter, $first: Int) {
+			books(filter: $filter, first: $first)
+		}`, tools[0].Query)
+
+	prelude, err = Prelude(&ast.Source{
+		Input: `
+directive @mcpTool(name: String, description: String, hidden: Boolean) on FIELD_DEFINITION
+directive @mcpArg(description: String) on ARGUMENT_DEFINITION | INPUT_FIELD_DEFINITION`,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, prelude)
+}
+
+func TestCustomScalars(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+scalar UUID
+scalar JSON
+scalar Money
+scalar Color
+scalar Int64
+
+input BookInput {
+  "Identifier of the book."
+  id: UUID!
+  metadata: JSON
+  prices: [Money!]
+}
+
+type Query {
+  book(
+    "Identifier of the book."
+    id: UUID!
+    input: BookInput
+    price: Money
+    color: Color
+    copies: Int64
+  ): JSON
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema, WithScalars(map[string]Scalar{
+		"Money": {Type: TypeInteger, Description: "Amount in cents."},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "description": "Identifier of the book.", "format": "uuid"},
+			"input": {"anyOf": [{"$ref": "#/$defs/BookInput"}, {"type": "null"}]},
+			"price": {"type": ["integer", "null"], "description": "Amount in cents."},
+			"color": {"type": ["string", "null"]},
+			"copies": {"type": ["integer", "null"]}
+		},
+		"required": ["id"],
+		"$defs": {
+			"BookInput": {
+				"type": "object",
+				"properties": {
+					"id": {"type": "string", "description": "Identifier of the book.", "format": "uuid"},
+					"metadata": {"type": ["object", "null"]},
+					"prices": {"type": ["array", "null"], "items": {"type": "integer", "description": "Amount in cents."}}
+				},
+				"required": ["id"]
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+	compareQueries(t,
+		`query book ($id: UUID!, $input: BookInput, $price: Money, $color: Color, $copies: Int64) {
+			book(id: $id, input: $input, price: $price, color: $color, copies: $copies)
+		}`, tools[0].Query)
+
+	scalarType, err := ParseType("integer")
+	assert.NoError(t, err)
+	assert.Equal(t, TypeInteger, scalarType)
+	_, err = ParseType("date")
+	assert.ErrorContains(t, err, "unsupported type date")
+}
+
+func TestDeprecated(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{
+		Input: `
+enum Status {
+  AVAILABLE
+  SOLD_OUT @deprecated(reason: "Use UNAVAILABLE.")
+  UNAVAILABLE
+}
+
+input BookFilter {
+  status: Status
+  author: String @deprecated
+}
+
+type Book {
+  title: String!
+  isbn: String @deprecated(reason: "Use ids.")
+}
+
+type Query {
+  "Retrieves a list of books."
+  books(filter: BookFilter, first: Int @deprecated): [Book!]!
+  allBooks: [Book!]! @deprecated(reason: "Use books.")
+}`,
+	})
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema, WithDeprecated(DeprecatedMark))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	assert.Equal(t, "allBooks", tools[1].Name)
+	assert.Equal(t, "Deprecated: Use books.", tools[1].Description)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/BookFilter"}, {"type": "null"}]},
+			"first": {"type": ["integer", "null"], "description": "Deprecated: No longer supported", "deprecated": true}
+		},
+		"$defs": {
+			"BookFilter": {
+				"type": "object",
+				"properties": {
+					"status": {
+						"type": ["string", "null"],
+						"enum": ["AVAILABLE", "SOLD_OUT", "UNAVAILABLE", null],
+						"oneOf": [
+							{"const": "AVAILABLE"},
+							{"const": "SOLD_OUT", "description": "Deprecated: Use UNAVAILABLE.", "deprecated": true},
+							{"const": "UNAVAILABLE"},
+							{"type": "null"}
+						]
+					},
+					"author": {"type": ["string", "null"], "description": "Deprecated: No longer supported", "deprecated": true}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+
+	tools, err = GetToolsForSchema(schema, WithDeprecated(DeprecatedExclude))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, "Retrieves a list of books.", tools[0].Description)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"filter": {"anyOf": [{"$ref": "#/$defs/BookFilter"}, {"type": "null"}]}
+		},
+		"$defs": {
+			"BookFilter": {
+				"type": "object",
+				"properties": {
+					"status": {"type": ["string", "null"], "enum": ["AVAILABLE", "UNAVAILABLE", null]}
+				}
+			}
+		}
+	}`, inputSchemaJSON(t, tools[0]))
+	compareQueries(t,
+		`query books ($filter: BookFilter) {
+			books(filter: $filter) {
+				title
+			}
+		}`, tools[0].Query)
+
+	_, err = ParseDeprecated("hide")
+	assert.ErrorContains(t, err, "unsupported value hide")
+}
+
+func TestToolErrors(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+input BookFilter {
+  title: String
+}
+
+type Book {
+  title: String!
+}
+
+type Query {
+  books(filter: BookFilter): [Book!]!
+  book(title: String!): Book @mcpTool(name: "get-book")
+  stats: String
+}`,
+	}
+	prelude, err := Prelude(source)
+	assert.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+	delete(schema.Types, "BookFilter")
+
+	tools, err := GetToolsForSchema(schema)
+	assert.Nil(t, tools)
+	assert.ErrorIs(t, err, ErrTypeNotFound)
+	assert.ErrorIs(t, err, ErrInvalidToolName)
+	var toolErr *Error
+	assert.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, QueryResolver, toolErr.OperationType)
+	assert.Equal(t, "books", toolErr.Operation)
+	assert.Equal(t, []string{"$filter"}, toolErr.Path)
+	assert.Equal(t, "BookFilter", toolErr.Type)
+	assert.Equal(t, "query books, $filter, type BookFilter: type not found in schema\n"+
+		"query book: tool names must be valid GraphQL names, got get-book", err.Error())
+
+	warnings := []error{}
+	tools, err = GetToolsForSchema(schema, WithSkipInvalid(func(err error) {
+		warnings = append(warnings, err)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tools))
+	assert.Equal(t, "stats", tools[0].Name)
+	assert.Equal(t, 2, len(warnings))
+}
+
+func TestDuplicateToolNames(t *testing.T) {
+	t.Parallel()
+
+	source := &ast.Source{
+		Input: `
+type Book {
+  title: String!
+}
+
+type Query {
+  books: [Book!]!
+  book(id: ID!): Book @mcpTool(name: "books")
+  stats: String
+}
+
+type Mutation {
+  stats: String
+}`,
+	}
+	prelude, err := Prelude(source)
+	assert.NoError(t, err)
+	schema, err := gqlparser.LoadSchema(source, prelude)
+	assert.NoError(t, err)
+
+	tools, err := GetToolsForSchema(schema)
+	assert.Nil(t, tools)
+	assert.ErrorIs(t, err, ErrDuplicateToolName)
+	assert.Equal(t, "query book: duplicate tool name books, also used by query books\n"+
+		"mutation stats: duplicate tool name stats, also used by query stats", err.Error())
+
+	tools, err = GetToolsForSchema(schema, WithSkipInvalid(func(error) {}))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(tools))
+	assert.Equal(t, "books", tools[0].Operation)
+}
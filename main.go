@@ -2,116 +2,297 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strings"
 
-	"github.com/vektah/gqlparser/v2"
-	"github.com/vektah/gqlparser/v2/ast"
-	"gopkg.in/yaml.v3"
-
 	"github.com/wimspaargaren/gql-gen-mcp/internal/gen"
+	"github.com/wimspaargaren/gql-gen-mcp/internal/tools"
+)
+
+// Exit codes of the gql-gen-mcp binary.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
 )
 
-// Root represents the root of the YAML configuration file.
-type Root struct {
-	Schemas []Schema `yaml:"schemas"`
+// version can be set at build time using -ldflags "-X main.version=v1.0.0".
+var version = "" //nolint:gochecknoglobals // set at build time.
+
+const usageText = `gql-gen-mcp generates Model Context Protocol (MCP) servers based on GraphQL schemas.
+
+Usage:
+  gql-gen-mcp <command> [flags]
+
+Commands:
+  generate  Generate the MCP server code (default command)
+  validate  Validate the configuration and schemas without generating code
+  init      Create a new configuration file
+  version   Print the version
+
+Run 'gql-gen-mcp <command> -h' for the flags of a command.
+`
+
+// usageError indicates the command line arguments are invalid.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
 }
 
-// Schema represents a schema configuration in the YAML file.
-type Schema struct {
-	Name   string `yaml:"name"`
-	Dir    string `yaml:"dir"`
-	Output string `yaml:"output"`
+func (e usageError) Unwrap() error {
+	return e.err
 }
 
 func main() {
-	schemaConfigurations, err := parseYamlFile()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	command := "generate"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "generate":
+		err = generateCommand(args, stdout, stderr)
+	case "validate":
+		err = validateCommand(args, stdout, stderr)
+	case "init":
+		err = initCommand(args, stdout, stderr)
+	case "version":
+		_, err = fmt.Fprintln(stdout, "gql-gen-mcp", buildVersion())
+	case "help":
+		_, err = fmt.Fprint(stdout, usageText)
+	default:
+		err = usageError{err: fmt.Errorf("unknown command %q", command)}
+	}
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageError{}):
+		_, _ = fmt.Fprintf(stderr, "gql-gen-mcp: %s\n\n%s", err, usageText)
+		return exitUsage
+	default:
+		_, _ = fmt.Fprintf(stderr, "gql-gen-mcp: %s\n", err)
+		return exitError
+	}
+}
+
+// schemaFlags are the flags used to select the schemas to operate on.
+type schemaFlags struct {
+	config    string
+	schemaDir string
+	name      string
+	output    string
+}
+
+func (f *schemaFlags) register(flags *flag.FlagSet, withOutput bool) {
+	flags.StringVar(&f.config, "config", defaultConfigFile, "path to the configuration file")
+	flags.StringVar(&f.schemaDir, "schema-dir", "", "directory containing the .graphql schema files including subdirectories, the configuration file is ignored when set")
+	flags.StringVar(&f.name, "name", "", "name of the schema in the configuration file to use")
+	if withOutput {
+		flags.StringVar(&f.output, "output", "", "directory where the generated code is written")
+	}
+}
+
+// schemas returns the schemas selected by the flags.
+func (f *schemaFlags) schemas() ([]Schema, error) {
+	if f.schemaDir != "" {
+		name := f.name
+		if name == "" {
+			name = filepath.Base(filepath.Clean(f.schemaDir))
+		}
+		return []Schema{{Name: name, Dir: f.schemaDir, Output: f.output}}, nil
+	}
+
+	root, err := loadConfig(f.config)
 	if err != nil {
-		log.Fatalf("error parsing yaml file: %s", err)
+		return nil, err
 	}
-	for _, schemaConf := range schemaConfigurations {
-		generator := gen.NewGenerator(schemaConf.Schema,
-			gen.WithOutputDir(schemaConf.OutputDirectory),
-		)
-		err := generator.Generate()
-		if err != nil {
-			log.Fatalf("unable to generate mcp server code: %s", err)
+	schemas := root.Schemas
+	if f.name != "" {
+		schemas = []Schema{}
+		for _, s := range root.Schemas {
+			if s.Name == f.name {
+				schemas = append(schemas, s)
+			}
+		}
+		if len(schemas) == 0 {
+			return nil, fmt.Errorf("schema %s not found in %s", f.name, f.config)
+		}
+	}
+	if len(schemas) == 0 {
+		return nil, fmt.Errorf("no schemas configured in %s", f.config)
+	}
+	if f.output != "" {
+		if len(schemas) > 1 {
+			return nil, usageError{err: errors.New("-output can only be used for a single schema, select one using -name")}
 		}
+		schemas[0].Output = f.output
 	}
+	return schemas, nil
 }
 
-// SchemaConfiguration represents the configuration for a schema.
-type SchemaConfiguration struct {
-	Schema          *ast.Schema
-	OutputDirectory string
+func newFlagSet(name, description string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: gql-gen-mcp %s [flags]\n\n%s\n\nFlags:\n", name, description)
+		flags.PrintDefaults()
+	}
+	return flags
 }
 
-func parseYamlFile() ([]*SchemaConfiguration, error) {
-	res := []*SchemaConfiguration{}
-	data, err := os.ReadFile(".gql-gen-mcp.yaml")
-	if err != nil {
-		return nil, fmt.Errorf("no .gql-gen-mcp.yaml file found: %w", err)
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return err
 	}
-	t := Root{}
-	err = yaml.Unmarshal(data, &t)
 	if err != nil {
-		return nil, fmt.Errorf("incorrect yaml file: %w", err)
+		return usageError{err: err}
+	}
+	if flags.NArg() > 0 {
+		return usageError{err: fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))}
 	}
+	return nil
+}
 
-	for i := 0; i < len(t.Schemas); i++ {
-		schema := t.Schemas[i]
-		schemaConf, err := readSchema(schema)
+func generateCommand(args []string, stdout, stderr io.Writer) error {
+	f := &schemaFlags{}
+	flags := newFlagSet("generate", "Generate the MCP server code for the configured schemas.", stderr)
+	f.register(flags, true)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	schemas, err := f.schemas()
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		if schema.Output == "" {
+			return fmt.Errorf("schema %s: no output directory configured", schema.Name)
+		}
+		generator, err := newGenerator(schema, stderr)
 		if err != nil {
-			return nil, fmt.Errorf("error reading schema: %s %w", schema.Name, err)
+			return err
 		}
+		err = generator.Generate()
+		if err != nil {
+			return fmt.Errorf("schema %s: unable to generate mcp server code: %w", schema.Name, err)
+		}
+		_, _ = fmt.Fprintf(stdout, "generated %s in %s\n", schema.Name, schema.Output)
+	}
+	return nil
+}
 
-		res = append(res, schemaConf)
+func validateCommand(args []string, stdout, stderr io.Writer) error {
+	f := &schemaFlags{}
+	flags := newFlagSet("validate", "Validate the configuration and schemas without generating code.", stderr)
+	f.register(flags, false)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	schemas, err := f.schemas()
+	if err != nil {
+		return err
+	}
+	for _, schema := range schemas {
+		generator, err := newGenerator(schema, stderr)
+		if err != nil {
+			return err
+		}
+		if err := generator.Validate(); err != nil {
+			return newToolsError(fmt.Sprintf("schema %s: invalid queries", schema.Name), err)
+		}
+		if schema.Resources {
+			_, _ = fmt.Fprintf(stdout, "%s: ok, %d tools, %d resources\n", schema.Name, len(generator.Tools()), len(generator.Resources()))
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "%s: ok, %d tools\n", schema.Name, len(generator.Tools()))
 	}
-	return res, nil
+	return nil
 }
 
-func readSchema(schema Schema) (*SchemaConfiguration, error) {
-	schemaText, err := schemaText(schema)
+func newGenerator(schema Schema, stderr io.Writer) (*gen.Generator, error) {
+	schemaConf, err := readSchema(schema)
 	if err != nil {
-		return nil, fmt.Errorf("error reading schema: %s %w", schema.Name, err)
-	}
-	// ignore federated annotations, as they cannot be parsed by gqlparser.
-	schemaText = strings.ReplaceAll(schemaText, "@key", "")
-	schemaText = strings.ReplaceAll(schemaText, "@external", "")
-	gqlSchema, err := gqlparser.LoadSchema(&ast.Source{
-		Input: schemaText,
-	})
+		return nil, fmt.Errorf("schema %s: %w", schema.Name, err)
+	}
+	toolOptions := schemaConf.ToolOptions
+	if schema.SkipInvalid {
+		toolOptions = append(toolOptions, tools.WithSkipInvalid(func(err error) {
+			_, _ = fmt.Fprintf(stderr, "gql-gen-mcp: warning: schema %s: skipping %s\n", schema.Name, err)
+		}))
+	}
+	options := []gen.Option{
+		gen.WithOutputDir(schemaConf.OutputDirectory),
+		gen.WithToolOptions(toolOptions...),
+		gen.WithBaseURL(schemaConf.BaseURL),
+	}
+	if schema.Resources {
+		options = append(options, gen.WithResources(schema.Name))
+	}
+	generator, err := gen.NewGenerator(schemaConf.Schema, options...)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing schema graphql: %w", err)
+		return nil, newToolsError(fmt.Sprintf("schema %s: unable to generate tools", schema.Name), err)
 	}
-	return &SchemaConfiguration{
-		Schema:          gqlSchema,
-		OutputDirectory: schema.Output,
-	}, nil
+	return generator, nil
 }
 
-func schemaText(schema Schema) (string, error) {
-	dirEntry, err := os.ReadDir(schema.Dir)
+const initTemplate = `schemas:
+  # name of the schema.
+  - name: %s
+    # directory containing the .graphql or .graphqls schema files, subdirectories are included.
+    dir: %s
+    # directory where the MCP server code is generated.
+    output: %s
+`
+
+func initCommand(args []string, stdout, stderr io.Writer) error {
+	var config, schemaDir, name, output string
+	var force bool
+	flags := newFlagSet("init", "Create a new configuration file.", stderr)
+	flags.StringVar(&config, "config", defaultConfigFile, "path of the configuration file to create")
+	flags.StringVar(&schemaDir, "schema-dir", "./graphql", "directory containing the .graphql schema files")
+	flags.StringVar(&name, "name", "api", "name of the schema")
+	flags.StringVar(&output, "output", "", "directory where the generated code is written (default ./mcp/<name>)")
+	flags.BoolVar(&force, "force", false, "overwrite an existing configuration file")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if output == "" {
+		output = "./mcp/" + name
+	}
+	if _, err := os.Stat(config); err == nil && !force {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", config)
+	}
+	err := os.WriteFile(config, []byte(fmt.Sprintf(initTemplate, name, schemaDir, output)), 0o600)
 	if err != nil {
-		return "", fmt.Errorf("error while reading directory: %s %w", schema.Dir, err)
+		return fmt.Errorf("error writing configuration file: %w", err)
 	}
-	totalSchema := ""
-	for _, entry := range dirEntry {
-		if entry.IsDir() {
-			continue
-		}
-		if !strings.HasSuffix(entry.Name(), ".graphql") &&
-			!strings.HasSuffix(entry.Name(), ".graphqls") {
-			continue
-		}
-		b, err := os.ReadFile(filepath.Join(schema.Dir, entry.Name()))
-		if err != nil {
-			return "", fmt.Errorf("error reading file: %s %w", entry.Name(), err)
-		}
-		totalSchema += string(b) + "\n"
+	_, _ = fmt.Fprintf(stdout, "created %s\n", config)
+	return nil
+}
+
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" {
+		return "(devel)"
 	}
-	return totalSchema, nil
+	return info.Main.Version
 }
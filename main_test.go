@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleSchemaDir = "example/bookstore-api/schema"
+
+func runCommand(args ...string) (int, string, string) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	code := run(args, stdout, stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunVersion(t *testing.T) {
+	t.Parallel()
+
+	code, stdout, _ := runCommand("version")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout, "gql-gen-mcp ")
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	t.Parallel()
+
+	code, _, stderr := runCommand("bogus")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown command "bogus"`)
+
+	code, _, stderr = runCommand("generate", "-unknown")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "flag provided but not defined: -unknown")
+
+	code, _, _ = runCommand("validate", "-h")
+	assert.Equal(t, exitOK, code)
+}
+
+func TestRunMissingConfig(t *testing.T) {
+	t.Parallel()
+
+	code, _, stderr := runCommand("validate", "-config", filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "missing.yaml file found")
+}
+
+func TestRunInitAndValidate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, defaultConfigFile)
+	schemaDir, err := filepath.Abs(exampleSchemaDir)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("init", "-config", config, "-schema-dir", schemaDir, "-name", "bookstore")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "created "+config)
+
+	code, _, stderr = runCommand("init", "-config", config)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "already exists")
+
+	code, stdout, stderr = runCommand("validate", "-config", config)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 7 tools\n", stdout)
+
+	code, _, stderr = runCommand("validate", "-config", config, "-name", "other")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "schema other not found")
+}
+
+func TestRunGenerateWithSchemaDir(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "mcp")
+	code, stdout, stderr := runCommand("generate", "-schema-dir", exampleSchemaDir, "-output", output)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "generated schema in "+output+"\n", stdout)
+	assert.FileExists(t, filepath.Join(output, "tools.go"))
+	assert.FileExists(t, filepath.Join(output, "main.go"))
+
+	code, _, stderr = runCommand("generate", "-schema-dir", exampleSchemaDir)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no output directory configured")
+}
+
+func TestLoadConfigResolvesPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, defaultConfigFile)
+	err := os.WriteFile(config, []byte(`schemas:
+  - name: api
+    dir: ./graphql
+    output: /tmp/mcp
+`), 0o600)
+	require.NoError(t, err)
+
+	root, err := loadConfig(config)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "graphql"), root.Schemas[0].Dir)
+	assert.Equal(t, "/tmp/mcp", root.Schemas[0].Output)
+}
+
+func TestRunReportsSchemaDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "types"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "query.graphql"), []byte("type Query {\n  books: [Book!]!\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types", "book.graphql"), []byte("type Book {\n  author: Author\n}\n"), 0o600))
+
+	code, _, stderr := runCommand("validate", "-schema-dir", dir)
+	assert.Equal(t, exitError, code)
+	assert.Equal(t,
+		"gql-gen-mcp: schema "+filepath.Base(dir)+": error parsing schema graphql:\n  "+
+			filepath.Join(dir, "types", "book.graphql")+":2:11: Undefined type Author.\n",
+		stderr)
+}
+
+func TestRunWithGqlgenConfig(t *testing.T) {
+	t.Parallel()
+
+	gqlgenConfig, err := filepath.Abs("example/bookstore-api/schema/gqlgen.yml")
+	require.NoError(t, err)
+	config := filepath.Join(t.TempDir(), defaultConfigFile)
+	err = os.WriteFile(config, []byte("schemas:\n  - name: bookstore\n    gqlgen_config: "+gqlgenConfig+"\n"), 0o600)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("validate", "-config", config)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 7 tools\n", stdout)
+}
+
+func TestRunWithResources(t *testing.T) {
+	t.Parallel()
+
+	gqlgenConfig, err := filepath.Abs("example/bookstore-api/schema/gqlgen.yml")
+	require.NoError(t, err)
+	output := t.TempDir()
+	config := filepath.Join(t.TempDir(), defaultConfigFile)
+	err = os.WriteFile(config, []byte("schemas:\n  - name: bookstore\n    gqlgen_config: "+gqlgenConfig+
+		"\n    resources: true\n    output: "+output+"\n"), 0o600)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("validate", "-config", config)
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 7 tools, 2 resources\n", stdout)
+
+	code, _, stderr = runCommand("generate", "-config", config)
+	require.Equal(t, exitOK, code, stderr)
+	b, err := os.ReadFile(filepath.Join(output, "tools.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"graphql://bookstore/schema"`)
+	assert.Contains(t, string(b), `"graphql://bookstore/Author/{id}"`)
+}
+
+func TestLoadGqlgenConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gqlgen.yml")
+	err := os.WriteFile(path, []byte("schema: graph/**/*.graphqls\nfederation:\n  filename: graph/federation.go\n  version: 2\n"), 0o600)
+	require.NoError(t, err)
+
+	config, err := loadGqlgenConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, stringList{filepath.Join(dir, "graph", "**", "*.graphqls")}, config.Schema)
+	assert.True(t, config.federationEnabled())
+
+	err = os.WriteFile(path, []byte("exec:\n  filename: generated.go\n"), 0o600)
+	require.NoError(t, err)
+	config, err = loadGqlgenConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, stringList{filepath.Join(dir, "schema.graphql")}, config.Schema)
+	assert.False(t, config.federationEnabled())
+}
+
+func TestRunWithToolFilters(t *testing.T) {
+	t.Parallel()
+
+	schemaDir, err := filepath.Abs(exampleSchemaDir)
+	require.NoError(t, err)
+	config := filepath.Join(t.TempDir(), defaultConfigFile)
+	err = os.WriteFile(config, []byte(`schemas:
+  - name: bookstore
+    dir: `+schemaDir+`
+    exclude:
+      - /^(update|delete)/
+    no_mutations: true
+  - name: invalid
+    dir: `+schemaDir+`
+    include:
+      - /[/
+`), 0o600)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("validate", "-config", config, "-name", "bookstore")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 4 tools\n", stdout)
+
+	code, _, stderr = runCommand("validate", "-config", config, "-name", "invalid")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "schema invalid: invalid include: invalid regular expression /[/")
+}
+
+func TestRunWithSelectionLimits(t *testing.T) {
+	t.Parallel()
+
+	schemaDir, err := filepath.Abs(exampleSchemaDir)
+	require.NoError(t, err)
+	config := filepath.Join(t.TempDir(), defaultConfigFile)
+	err = os.WriteFile(config, []byte(`schemas:
+  - name: bookstore
+    dir: `+schemaDir+`
+    max_depth: 2
+    max_fields: 20
+    cycle_depth: 1
+    subscriptions:
+      enabled: true
+      max_events: 10
+      timeout: 1m
+    tools:
+      books:
+        max_fields: 5
+      book:
+        selection:
+          fields: id title author { name }
+  - name: invalid
+    dir: `+schemaDir+`
+    tools:
+      books:
+        max_depth: -1
+  - name: unknown
+    dir: `+schemaDir+`
+    tools:
+      bookz:
+        selection:
+          fields: nonexistent { bogus }
+`), 0o600)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("validate", "-config", config, "-name", "bookstore")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 7 tools\n", stdout)
+
+	code, _, stderr = runCommand("validate", "-config", config, "-name", "invalid")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "tool books: invalid max_depth -1, must be positive")
+
+	code, _, stderr = runCommand("validate", "-config", config, "-name", "unknown")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "configured tool matches no operation: bookz")
+}
+
+func TestRunWithOperations(t *testing.T) {
+	t.Parallel()
+
+	schemaDir, err := filepath.Abs(exampleSchemaDir)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	operations := filepath.Join(dir, "operations")
+	require.NoError(t, os.Mkdir(operations, 0o750))
+	empty := filepath.Join(dir, "empty")
+	require.NoError(t, os.Mkdir(empty, 0o750))
+	err = os.WriteFile(filepath.Join(operations, "books.graphql"), []byte(`
+query bookWithAuthor($id: ID!) {
+  book(id: $id) {
+    title
+    author { name }
+  }
+}`), 0o600)
+	require.NoError(t, err)
+	config := filepath.Join(dir, defaultConfigFile)
+	err = os.WriteFile(config, []byte(`schemas:
+  - name: bookstore
+    dir: `+schemaDir+`
+    operations: `+operations+`
+  - name: relative
+    dir: `+schemaDir+`
+    operations: ./operations
+  - name: empty
+    dir: `+schemaDir+`
+    operations: `+empty+`
+`), 0o600)
+	require.NoError(t, err)
+
+	code, stdout, stderr := runCommand("validate", "-config", config, "-name", "bookstore")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "bookstore: ok, 1 tools\n", stdout)
+
+	code, stdout, stderr = runCommand("validate", "-config", config, "-name", "relative")
+	require.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "relative: ok, 1 tools\n", stdout)
+
+	code, _, stderr = runCommand("validate", "-config", config, "-name", "empty")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "no operation documents found in "+empty)
+}
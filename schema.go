@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/wimspaargaren/gql-gen-mcp/internal/federation"
+	"github.com/wimspaargaren/gql-gen-mcp/internal/introspection"
+	"github.com/wimspaargaren/gql-gen-mcp/internal/source"
+	"github.com/wimspaargaren/gql-gen-mcp/internal/tools"
+)
+
+const introspectionTimeout = 30 * time.Second
+
+// SchemaConfiguration represents the configuration for a schema.
+type SchemaConfiguration struct {
+	Schema          *ast.Schema
+	OutputDirectory string
+	BaseURL         string
+	ToolOptions     []tools.Option
+}
+
+func readSchema(schema Schema) (*SchemaConfiguration, error) {
+	toolOptions, err := toolOptions(schema)
+	if err != nil {
+		return nil, err
+	}
+	schemaConf, err := loadSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	schemaConf.ToolOptions = append(schemaConf.ToolOptions, toolOptions...)
+	if schema.Operations != "" {
+		operations, err := loadOperations(schema.Operations)
+		if err != nil {
+			return nil, err
+		}
+		schemaConf.ToolOptions = append(schemaConf.ToolOptions, tools.WithOperations(operations...))
+	}
+	return schemaConf, nil
+}
+
+// loadOperations loads the operation documents in the directory and its subdirectories.
+func loadOperations(dir string) ([]*ast.Source, error) {
+	files, err := source.Glob(source.DirPatterns(dir)...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading operations: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no operation documents found in %s", dir)
+	}
+	operations, err := source.Load(files...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading operations: %w", err)
+	}
+	return operations, nil
+}
+
+// toolOptions returns the tool options selecting the operations for which tools
+// are generated, configuring the representation of custom scalars and deprecated items and
+// limiting or overriding the selection sets of the responses.
+func toolOptions(schema Schema) ([]tools.Option, error) {
+	options := []tools.Option{}
+	include, err := tools.ParsePatterns(schema.Include...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include: %w", err)
+	}
+	if len(include) > 0 {
+		options = append(options, tools.WithInclude(include...))
+	}
+	exclude, err := tools.ParsePatterns(schema.Exclude...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude: %w", err)
+	}
+	if len(exclude) > 0 {
+		options = append(options, tools.WithExclude(exclude...))
+	}
+	if schema.QueriesOnly {
+		options = append(options, tools.WithQueriesOnly())
+	}
+	if schema.NoMutations {
+		options = append(options, tools.WithoutMutations())
+	}
+	if len(schema.Scalars) > 0 {
+		scalars := map[string]tools.Scalar{}
+		for name, s := range schema.Scalars {
+			scalarType, err := tools.ParseType(s.Type)
+			if err != nil {
+				return nil, fmt.Errorf("invalid scalar %s: %w", name, err)
+			}
+			scalars[name] = tools.Scalar{
+				Type:        scalarType,
+				Format:      s.Format,
+				Pattern:     s.Pattern,
+				Description: s.Description,
+			}
+		}
+		options = append(options, tools.WithScalars(scalars))
+	}
+	deprecated, err := tools.ParseDeprecated(schema.Deprecated)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deprecated: %w", err)
+	}
+	options = append(options, tools.WithDeprecated(deprecated))
+	limits, err := selectionLimits(schema.MaxDepth, schema.MaxFields)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, tools.WithSelectionLimits(limits))
+	subscriptions, err := subscriptionOptions(schema.Subscriptions)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, subscriptions...)
+	if schema.CycleDepth < 0 {
+		return nil, fmt.Errorf("invalid cycle_depth %d, must be positive", schema.CycleDepth)
+	}
+	options = append(options, tools.WithCycleDepth(schema.CycleDepth))
+	for name, tool := range schema.Tools {
+		limits, err := selectionLimits(tool.MaxDepth, tool.MaxFields)
+		if err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+		options = append(options, tools.WithToolSelectionLimits(name, limits))
+		selection := tool.Selection
+		if selection.Fields != "" && (len(selection.Include) > 0 || len(selection.Exclude) > 0) {
+			return nil, fmt.Errorf("tool %s: selection fields cannot be combined with include or exclude", name)
+		}
+		options = append(options, tools.WithToolSelection(name, tools.Selection{
+			Fields:  selection.Fields,
+			Include: selection.Include,
+			Exclude: selection.Exclude,
+		}))
+	}
+	return options, nil
+}
+
+// subscriptionOptions returns the options bounding the events collected by subscription tools, and enabling
+// these tools if configured. The bounds also apply to subscriptions in operation documents.
+func subscriptionOptions(subscriptions Subscriptions) ([]tools.Option, error) {
+	if subscriptions.MaxEvents < 0 {
+		return nil, fmt.Errorf("invalid subscriptions max_events %d, must be positive", subscriptions.MaxEvents)
+	}
+	if subscriptions.Timeout < 0 {
+		return nil, fmt.Errorf("invalid subscriptions timeout %s, must be positive", subscriptions.Timeout)
+	}
+	options := []tools.Option{tools.WithSubscriptions(tools.Subscriptions{
+		MaxEvents: subscriptions.MaxEvents,
+		Timeout:   subscriptions.Timeout,
+		Progress:  subscriptions.Progress,
+	})}
+	if subscriptions.Enabled {
+		options = append(options, tools.WithSubscriptionTools())
+	}
+	return options, nil
+}
+
+func selectionLimits(maxDepth, maxFields int) (tools.SelectionLimits, error) {
+	if maxDepth < 0 {
+		return tools.SelectionLimits{}, fmt.Errorf("invalid max_depth %d, must be positive", maxDepth)
+	}
+	if maxFields < 0 {
+		return tools.SelectionLimits{}, fmt.Errorf("invalid max_fields %d, must be positive", maxFields)
+	}
+	return tools.SelectionLimits{MaxDepth: maxDepth, MaxFields: maxFields}, nil
+}
+
+func loadSchema(schema Schema) (*SchemaConfiguration, error) {
+	files, subgraphs := "", ""
+	if schema.Dir != "" || len(schema.Files) > 0 || schema.GqlgenConfig != "" {
+		files = "files"
+	}
+	if len(schema.Subgraphs) > 0 {
+		subgraphs = "subgraphs"
+	}
+	if countNonEmpty(files, schema.IntrospectionURL, schema.IntrospectionFile, subgraphs) > 1 {
+		return nil, errors.New("dir/files/gqlgen_config, introspection_url, introspection_file and subgraphs are mutually exclusive")
+	}
+	if schema.IntrospectionURL != "" || schema.IntrospectionFile != "" {
+		return readIntrospectionSchema(schema)
+	}
+	if len(schema.Subgraphs) > 0 {
+		return readSupergraphSchema(schema)
+	}
+	if files == "" {
+		return nil, errors.New("one of dir, files, gqlgen_config, introspection_url, introspection_file or subgraphs must be configured")
+	}
+	patterns := append([]string{}, schema.Files...)
+	if schema.Dir != "" {
+		patterns = append(source.DirPatterns(schema.Dir), patterns...)
+	}
+	if schema.GqlgenConfig != "" {
+		gqlgenConf, err := loadGqlgenConfig(schema.GqlgenConfig)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, gqlgenConf.Schema...)
+		schema.Federation.Enabled = schema.Federation.Enabled || gqlgenConf.federationEnabled()
+	}
+	sources, err := loadSources(patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema: %s %w", schema.Name, err)
+	}
+	isSubgraph, err := federation.IsSubgraph(sources...)
+	if err != nil {
+		return nil, newSchemaError("error parsing schema graphql", err)
+	}
+	if !isSubgraph && !schema.Federation.Enabled {
+		gqlSchema, err := gqlparser.LoadSchema(sources...)
+		if err != nil {
+			return nil, newSchemaError("error parsing schema graphql", err)
+		}
+		return &SchemaConfiguration{
+			Schema:          gqlSchema,
+			OutputDirectory: schema.Output,
+			BaseURL:         schema.BaseURL,
+		}, nil
+	}
+
+	gqlSchema, err := federation.LoadSchema(sources...)
+	if err != nil {
+		return nil, newSchemaError("error parsing subgraph schema graphql", err)
+	}
+	toolOptions := []tools.Option{}
+	if schema.Federation.EntityTools {
+		toolOptions = append(toolOptions, tools.WithEntityTools())
+	}
+	return &SchemaConfiguration{
+		Schema:          gqlSchema,
+		OutputDirectory: schema.Output,
+		BaseURL:         schema.BaseURL,
+		ToolOptions:     toolOptions,
+	}, nil
+}
+
+func readSupergraphSchema(schema Schema) (*SchemaConfiguration, error) {
+	if schema.Federation.EntityTools {
+		return nil, errors.New("entity tools are not supported for composed subgraphs, the gateway does not expose the _entities query")
+	}
+	subgraphs := []federation.Subgraph{}
+	for _, subgraph := range schema.Subgraphs {
+		patterns := []string{subgraph}
+		if !source.IsPattern(subgraph) {
+			patterns = source.DirPatterns(subgraph)
+		}
+		sources, err := loadSources(patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("error reading subgraph: %s %w", subgraph, err)
+		}
+		subgraphs = append(subgraphs, federation.Subgraph{
+			Name:    subgraph,
+			Sources: sources,
+		})
+	}
+	gqlSchema, err := federation.Compose(subgraphs...)
+	if err != nil {
+		return nil, newSchemaError("error composing subgraphs", err)
+	}
+	return &SchemaConfiguration{
+		Schema:          gqlSchema,
+		OutputDirectory: schema.Output,
+		BaseURL:         schema.BaseURL,
+	}, nil
+}
+
+// loadSources loads the schema files matching the patterns, together with the
+// definitions of the tool directives which are not defined by the schema itself.
+func loadSources(patterns ...string) ([]*ast.Source, error) {
+	sources, err := source.LoadGlob(patterns...)
+	if err != nil {
+		return nil, err
+	}
+	prelude, err := tools.Prelude(sources...)
+	if err != nil {
+		// Syntax errors are reported with their position when the schema is loaded.
+		return sources, nil //nolint:nilerr
+	}
+	if prelude != nil {
+		sources = append(sources, prelude)
+	}
+	return sources, nil
+}
+
+func readIntrospectionSchema(schema Schema) (*SchemaConfiguration, error) {
+	introspectionSchema, source, err := introspectionResult(schema)
+	if err != nil {
+		return nil, err
+	}
+	gqlSchema, err := introspection.BuildSchema(introspectionSchema, source)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaConfiguration{
+		Schema:          gqlSchema,
+		OutputDirectory: schema.Output,
+		BaseURL:         schema.BaseURL,
+	}, nil
+}
+
+func introspectionResult(schema Schema) (*introspection.Schema, string, error) {
+	if schema.IntrospectionFile != "" {
+		b, err := os.ReadFile(schema.IntrospectionFile)
+		if err != nil {
+			return nil, "", fmt.Errorf("error reading file: %s %w", schema.IntrospectionFile, err)
+		}
+		introspectionSchema, err := introspection.Parse(b)
+		if err != nil {
+			return nil, "", fmt.Errorf("error parsing introspection file: %s %w", schema.IntrospectionFile, err)
+		}
+		return introspectionSchema, schema.IntrospectionFile, nil
+	}
+
+	headers := map[string]string{}
+	for k, v := range schema.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), introspectionTimeout)
+	defer cancel()
+	introspectionSchema, err := introspection.Fetch(ctx, schema.IntrospectionURL, headers)
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching schema: %s %w", schema.IntrospectionURL, err)
+	}
+	return introspectionSchema, schema.IntrospectionURL, nil
+}
+
+func countNonEmpty(values ...string) int {
+	count := 0
+	for _, v := range values {
+		if v != "" {
+			count++
+		}
+	}
+	return count
+}